@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bubblecal/internal/config"
+	"bubblecal/internal/doctor"
+	"bubblecal/internal/storage"
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// runDoctor reports problems in the calendar directory at root, and
+// repairs them with -fix or, one by one, with -i:
+//
+//	bubblecal doctor [-fix | -i]
+func runDoctor(root string, args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "repair every problem that can be repaired")
+	ask := flags.Bool("i", false, "ask before repairing each problem")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if storage.IsEncrypted(root) {
+		return fmt.Errorf("can't check an encrypted calendar; run \"bubblecal decrypt\" first")
+	}
+	cfg, _ := config.Load()
+	problems, err := doctor.Check(root, cfg)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Println("No problems found in", root)
+		return nil
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	left := 0
+	for _, p := range problems {
+		fmt.Printf("%s %s\n", p.Path, p.Message)
+		if p.Warning {
+			continue
+		}
+		if p.Fix == "" {
+			left++
+			continue
+		}
+		repair := *fix
+		if *ask {
+			fmt.Printf("  %s? [y/N] ", p.Fix)
+			answer, _ := in.ReadString('\n')
+			repair = strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y")
+		} else if !*fix {
+			fmt.Printf("  fix: %s\n", p.Fix)
+		}
+		if !repair {
+			left++
+			continue
+		}
+		// Keep a running bubblecal from writing while the repair does
+		unlock, err := storage.LockDir(root)
+		if err == nil {
+			err = p.Repair()
+			unlock()
+		}
+		if err != nil {
+			fmt.Printf("  failed: %v\n", err)
+			left++
+			continue
+		}
+		fmt.Printf("  fixed: %s\n", p.Fix)
+	}
+
+	if left > 0 {
+		if !*fix && !*ask {
+			return fmt.Errorf("%d problems found; run \"bubblecal doctor -fix\" to repair them, or -i to choose", left)
+		}
+		return fmt.Errorf("%d problems left", left)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bubblecal/internal/config"
+	"bubblecal/internal/storage"
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// passphraseTries is how often a wrong passphrase may be entered
+const passphraseTries = 3
+
+// readPassphrase prompts for a passphrase on a terminal without echoing
+// it, or reads a line from standard input otherwise
+func readPassphrase(prompt string) (string, error) {
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("no passphrase given")
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(data), nil
+}
+
+// openEncrypted asks for the passphrase of the encrypted calendar at root
+// and opens it
+func openEncrypted(root string) (*storage.FileStore, error) {
+	for try := 1; ; try++ {
+		passphrase, err := readPassphrase("Passphrase for " + root + ": ")
+		if err != nil {
+			return nil, err
+		}
+		store, err := storage.NewEncryptedFileStore(root, passphrase)
+		if errors.Is(err, storage.ErrPassphrase) && try < passphraseTries && term.IsTerminal(os.Stdin.Fd()) {
+			fmt.Fprintln(os.Stderr, "Wrong passphrase, try again.")
+			continue
+		}
+		return store, err
+	}
+}
+
+// runEncrypt encrypts the calendar directory at root with a passphrase,
+// optionally replacing the titles in filenames by event IDs:
+//
+//	bubblecal encrypt [-names]
+func runEncrypt(root string, args []string) error {
+	flags := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	names := flags.Bool("names", false, "also keep titles out of filenames")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if cfg, _ := config.Load(); cfg.Storage == config.StorageText {
+		return fmt.Errorf("only the directory format can be encrypted; run \"bubblecal migrate dirs\" first")
+	}
+	if storage.IsEncrypted(root) {
+		return fmt.Errorf("%s is already encrypted", root)
+	}
+
+	passphrase, err := readPassphrase("New passphrase: ")
+	if err != nil {
+		return err
+	}
+	if term.IsTerminal(os.Stdin.Fd()) {
+		again, err := readPassphrase("Repeat the passphrase: ")
+		if err != nil {
+			return err
+		}
+		if again != passphrase {
+			return fmt.Errorf("the passphrases don't match")
+		}
+	}
+
+	n, err := storage.Encrypt(root, passphrase, *names)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Encrypted %d files in %s.\n", n, root)
+	fmt.Println("There is no way to recover the calendar without the passphrase.")
+	return nil
+}
+
+// runDecrypt turns the encrypted calendar directory at root back into
+// plain files:
+//
+//	bubblecal decrypt
+func runDecrypt(root string, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: bubblecal decrypt")
+	}
+	if !storage.IsEncrypted(root) {
+		return fmt.Errorf("%s isn't encrypted", root)
+	}
+	passphrase, err := readPassphrase("Passphrase for " + root + ": ")
+	if err != nil {
+		return err
+	}
+	n, err := storage.Decrypt(root, passphrase)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Decrypted %d files in %s.\n", n, root)
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bubblecal/internal/export"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// runExport writes events to an iCalendar file:
+//
+//	bubblecal export [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-o file.ics]
+func runExport(store storage.Store, args []string) error {
+	now := time.Now()
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	from := flags.String("from", now.AddDate(0, -1, 0).Format(model.DateFormat), "first date to export")
+	to := flags.String("to", now.AddDate(1, 0, 0).Format(model.DateFormat), "last date to export")
+	output := flags.String("o", "", "file to write (default: standard output)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	start, err := time.ParseInLocation(model.DateFormat, *from, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid -from date: %s", *from)
+	}
+	end, err := time.ParseInLocation(model.DateFormat, *to, time.Local)
+	if err != nil {
+		return fmt.Errorf("invalid -to date: %s", *to)
+	}
+	days, err := store.LoadRange(start, end)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return export.WriteICS(w, days)
+}
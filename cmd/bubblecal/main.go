@@ -2,16 +2,68 @@ package main
 
 import (
 	"log"
+	"os"
+	"bubblecal/internal/config"
+	"bubblecal/internal/storage"
 	"bubblecal/internal/tui"
+	_ "time/tzdata" // event time zones work without system zoneinfo
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	model := tui.NewModel()
+	var store storage.Store = storage.NewFileStore(storage.GetCalendarDir())
+	if cfg, _ := config.Load(); cfg.Storage == config.StorageText {
+		store = storage.NewTextStore(storage.GetTextDir())
+	} else if storage.IsEncrypted(storage.GetCalendarDir()) && (len(os.Args) == 1 || os.Args[1] == "export") {
+		// Only the calendar and export read events
+		fs, err := openEncrypted(storage.GetCalendarDir())
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		store = fs
+	}
+	
+	// Subcommands run without the TUI
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			if err := runExport(store, os.Args[2:]); err != nil {
+				log.Fatalf("export: %v", err)
+			}
+			return
+		case "doctor":
+			if err := runDoctor(storage.GetCalendarDir(), os.Args[2:]); err != nil {
+				log.Fatalf("doctor: %v", err)
+			}
+			return
+		case "encrypt":
+			if err := runEncrypt(storage.GetCalendarDir(), os.Args[2:]); err != nil {
+				log.Fatalf("encrypt: %v", err)
+			}
+			return
+		case "decrypt":
+			if err := runDecrypt(storage.GetCalendarDir(), os.Args[2:]); err != nil {
+				log.Fatalf("decrypt: %v", err)
+			}
+			return
+		case "migrate":
+			if err := runMigrate(os.Args[2:]); err != nil {
+				log.Fatalf("migrate: %v", err)
+			}
+			return
+		default:
+			log.Fatalf("unknown command: %s", os.Args[1])
+		}
+	}
+	
+	model := tui.NewModel(store)
 	program := tea.NewProgram(model, tea.WithAltScreen())
 	
 	if _, err := program.Run(); err != nil {
 		log.Fatalf("Error running program: %v", err)
 	}
-}
\ No newline at end of file
+	if err := model.Close(); err != nil {
+		log.Printf("git history: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bubblecal/internal/config"
+	"bubblecal/internal/storage"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runMigrate copies the calendar into the other storage format, switches
+// to it and moves the old files to ~/.bubblecal/backup:
+//
+//	bubblecal migrate text   one directory per day to one text file per day
+//	bubblecal migrate dirs   one text file per day to one directory per day
+func runMigrate(args []string) error {
+	if len(args) != 1 || (args[0] != "text" && args[0] != "dirs") {
+		return fmt.Errorf("usage: bubblecal migrate text|dirs")
+	}
+	cfg, _ := config.Load()
+	if storage.IsEncrypted(storage.GetCalendarDir()) {
+		return fmt.Errorf("can't migrate an encrypted calendar; run \"bubblecal decrypt\" first")
+	}
+
+	dirs := storage.NewFileStore(storage.GetCalendarDir())
+	text := storage.NewTextStore(storage.GetTextDir())
+	var from, to storage.Store = dirs, text
+	where, format, old := storage.GetTextDir(), config.StorageText, "dirs"
+	if args[0] == "dirs" {
+		from, to = text, dirs
+		where, format, old = storage.GetCalendarDir(), "", "text"
+	}
+	if cfg.Storage == format {
+		return fmt.Errorf("the calendar is already stored that way")
+	}
+
+	n, err := storage.Migrate(from, to)
+	if err != nil {
+		return err
+	}
+	cfg.Storage = format
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("copied the calendar to %s but couldn't switch to it: %w", where, err)
+	}
+	fmt.Printf("Copied %d events, %d notes and %d tasks to %s, which bubblecal now uses.\n", n.Events, n.Notes, n.Tasks, where)
+
+	// Put the old files away, so that migrating back finds them gone
+	backup := backupDir(old)
+	if err := storage.Archive(from, backup); err != nil {
+		return fmt.Errorf("couldn't move the old files to %s: %w", backup, err)
+	}
+	fmt.Println("The old files were moved to", backup)
+	return nil
+}
+
+// backupDir returns a directory under ~/.bubblecal/backup, not yet used,
+// for the old files of the given format
+func backupDir(format string) string {
+	base := filepath.Join(storage.GetCalendarDir(), "backup", format+"-"+time.Now().Format("2006-01-02-150405"))
+	dir := base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return dir
+		}
+		dir = fmt.Sprintf("%s-%d", base, n)
+	}
+}
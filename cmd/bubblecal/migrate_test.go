@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bubblecal/internal/config"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMigrateThereAndBack(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	date := time.Date(2025, 8, 13, 0, 0, 0, 0, time.Local)
+	dirs := storage.NewFileStore(storage.GetCalendarDir())
+	event := &model.Event{StartTime: "09:00", EndTime: "10:00", Title: "Standup", Location: "Room 4"}
+	if err := dirs.SaveEvent(date, event); err != nil {
+		t.Fatal(err)
+	}
+	if err := dirs.SaveNote(date, "Bring the numbers"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dirs.SaveTask(&model.Task{Title: "Send report", Due: "2025-08-20"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, step := range []struct {
+		format string
+		store  func() storage.Store
+	}{
+		{"text", func() storage.Store { return storage.NewTextStore(storage.GetTextDir()) }},
+		{"dirs", func() storage.Store { return storage.NewFileStore(storage.GetCalendarDir()) }},
+		{"text", func() storage.Store { return storage.NewTextStore(storage.GetTextDir()) }},
+	} {
+		if err := runMigrate([]string{step.format}); err != nil {
+			t.Fatalf("migrate %s: %v", step.format, err)
+		}
+		cfg, _ := config.Load()
+		if want := map[string]string{"text": config.StorageText, "dirs": ""}[step.format]; cfg.Storage != want {
+			t.Errorf("migrate %s: storage is %q", step.format, cfg.Storage)
+		}
+
+		store := step.store()
+		events, err := store.LoadDayEvents(date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || events[0].ID != event.ID || events[0].FormatFileContent() != event.FormatFileContent() {
+			t.Errorf("migrate %s: got %v, want the one event", step.format, events)
+		}
+		if note, _ := store.(storage.NoteStore).LoadNote(date); note != "Bring the numbers" {
+			t.Errorf("migrate %s: note is %q", step.format, note)
+		}
+		if tasks, _ := store.(storage.TaskStore).LoadTasks(); len(tasks) != 1 {
+			t.Errorf("migrate %s: got %d tasks", step.format, len(tasks))
+		}
+	}
+
+	// Each migration put the old files away
+	backups, _ := os.ReadDir(filepath.Join(storage.GetCalendarDir(), "backup"))
+	if len(backups) != 3 {
+		t.Errorf("got %d backups, want 3", len(backups))
+	}
+	if _, err := os.Stat(storage.GetDaysDir()); !os.IsNotExist(err) {
+		t.Errorf("days/ is still there after migrating to text")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // Category represents a calendar category with color
@@ -16,10 +17,23 @@ type Category struct {
 type Config struct {
 	ShowMiniMonth bool       `json:"show_mini_month"`
 	AgendaBottom  bool       `json:"agenda_bottom"`
+	ListNotes     bool       `json:"list_notes"` // show day note excerpts in the List view
 	Theme         int        `json:"theme"`
 	Categories    []Category `json:"categories"`
+	DisplayZone   string     `json:"display_zone"` // IANA zone times are shown in, "" for the system zone
+	TrashDays     int        `json:"trash_days"`   // days deleted events are kept, 0 for DefaultTrashDays, -1 for ever
+	GitHistory    bool       `json:"git_history"`  // commit every change to the calendar directory with git
+	Storage       string     `json:"storage"`      // StorageText for one text file per day, "" for a directory per day
+	
+	loc *time.Location // DisplayZone, loaded on first use
 }
 
+// DefaultTrashDays is how long deleted events are kept unless configured
+const DefaultTrashDays = 30
+
+// StorageText keeps events in one text file per day (see storage.TextStore)
+const StorageText = "text"
+
 // DefaultCategories returns the default set of categories
 func DefaultCategories() []Category {
 	return []Category{
@@ -59,6 +73,11 @@ func configPath() (string, error) {
 	return filepath.Join(configDir, "config.json"), nil
 }
 
+// Path returns the path of the config file
+func Path() (string, error) {
+	return configPath()
+}
+
 // Load loads the configuration from disk
 func Load() (*Config, error) {
 	path, err := configPath()
@@ -112,4 +131,32 @@ func (c *Config) GetCategoryColor(categoryName string) string {
 	}
 	// Default color if category not found
 	return "#808080" // Gray
-}
\ No newline at end of file
+}
+// Location returns the display time zone, falling back to the system
+// zone if none is set or it is unknown
+func (c *Config) Location() *time.Location {
+	if c.DisplayZone == "" {
+		return time.Local
+	}
+	if c.loc != nil && c.loc.String() == c.DisplayZone {
+		return c.loc
+	}
+	loc, err := time.LoadLocation(c.DisplayZone)
+	if err != nil {
+		return time.Local
+	}
+	c.loc = loc
+	return loc
+}
+
+// TrashRetention returns how long deleted events are kept, or 0 if they
+// are kept until purged by hand
+func (c *Config) TrashRetention() time.Duration {
+	switch {
+	case c.TrashDays < 0:
+		return 0
+	case c.TrashDays == 0:
+		return DefaultTrashDays * 24 * time.Hour
+	}
+	return time.Duration(c.TrashDays) * 24 * time.Hour
+}
@@ -0,0 +1,404 @@
+// Package doctor finds and repairs problems in the calendar directory
+// that the calendar itself skips over, such as files it can't read
+package doctor
+
+import (
+	"bubblecal/internal/config"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LostAndFound is the directory, under the calendar directory, that files
+// the doctor can't repair are moved to, keeping their paths
+const LostAndFound = "lost+found"
+
+// staleTemp is how old a temporary file must be to count as left over
+// from a write that never finished
+const staleTemp = time.Hour
+
+// Problem is one thing wrong in the calendar directory
+type Problem struct {
+	Path    string // relative to the calendar directory, "config.json" for categories
+	Message string
+	Fix     string // what Repair does, "" if it can't be repaired
+	Warning bool   // may well be intended, so it is only reported
+	repair  func() error
+}
+
+// Repair fixes the problem as Fix describes
+func (p *Problem) Repair() error {
+	if p.repair == nil {
+		return fmt.Errorf("%s can't be repaired", p.Path)
+	}
+	return p.repair()
+}
+
+// doctor holds what a check has seen so far
+type doctor struct {
+	root     string
+	cfg      *config.Config
+	problems []*Problem
+	ids      map[string]string   // event ID to the first file holding it
+	unknown  map[string][]string // unknown category to the files using it
+}
+
+// Check scans the day directories and the multi-day and recurring events
+// of the calendar at root. Categories are checked against cfg.
+func Check(root string, cfg *config.Config) ([]*Problem, error) {
+	d := &doctor{
+		root:    root,
+		cfg:     cfg,
+		ids:     make(map[string]string),
+		unknown: make(map[string][]string),
+	}
+
+	days, err := os.ReadDir(filepath.Join(root, "days"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read days directory: %w", err)
+	}
+	for _, day := range days {
+		rel := filepath.Join("days", day.Name())
+		if strings.HasPrefix(day.Name(), ".") {
+			continue // a day directory being replaced; the calendar finishes it
+		}
+		if _, err := time.Parse(model.DateFormat, day.Name()); err != nil || !day.IsDir() {
+			d.stray(rel, "isn't a day directory")
+			continue
+		}
+		d.checkDir(rel)
+	}
+	for _, dir := range []string{"spans", "recurring"} {
+		if _, err := os.Stat(filepath.Join(root, dir)); err == nil {
+			d.checkDir(dir)
+		}
+	}
+	d.checkCategories()
+	return d.problems, nil
+}
+
+func (d *doctor) add(p *Problem) {
+	d.problems = append(d.problems, p)
+}
+
+// stray reports a file that doesn't belong at rel, to be moved to the
+// lost and found
+func (d *doctor) stray(rel, why string) {
+	d.add(&Problem{
+		Path:    rel,
+		Message: why,
+		Fix:     "move it to " + LostAndFound,
+		repair:  func() error { return d.moveToLostAndFound(rel) },
+	})
+}
+
+// checkDir checks the event files of the directory at rel
+func (d *doctor) checkDir(rel string) {
+	entries, err := os.ReadDir(filepath.Join(d.root, rel))
+	if err != nil {
+		d.add(&Problem{Path: rel, Message: err.Error()})
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(rel, name)
+		switch {
+		case strings.HasPrefix(name, ".tmp-"):
+			if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > staleTemp {
+				d.add(&Problem{
+					Path:    path,
+					Message: "is left over from a write that never finished",
+					Fix:     "delete it",
+					repair:  func() error { return os.Remove(filepath.Join(d.root, path)) },
+				})
+			}
+			continue
+		case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+			continue // bookkeeping and day notes
+		case entry.IsDir():
+			d.stray(path, "is a directory among event files")
+			continue
+		}
+		d.checkFile(rel, name)
+	}
+
+	if len(entries) == 0 && strings.HasPrefix(rel, "days") {
+		d.add(&Problem{
+			Path:    rel,
+			Message: "is empty",
+			Fix:     "delete it",
+			repair:  func() error { return os.Remove(filepath.Join(d.root, rel)) },
+		})
+	}
+}
+
+// checkFile checks the event file name in the directory at rel
+func (d *doctor) checkFile(rel, name string) {
+	path := filepath.Join(rel, name)
+	content, err := os.ReadFile(filepath.Join(d.root, path))
+	if err != nil {
+		d.add(&Problem{Path: path, Message: err.Error()})
+		return
+	}
+	event, err := parse(rel, name, string(content))
+	if err != nil {
+		d.stray(path, "can't be read: "+err.Error())
+		return
+	}
+
+	// Times
+	if !event.IsAllDay() {
+		start, startErr := time.Parse("15:04", event.StartTime)
+		end, endErr := time.Parse("15:04", event.EndTime)
+		switch {
+		case startErr != nil:
+			d.add(&Problem{
+				Path:    path,
+				Message: fmt.Sprintf("has an invalid start time %q", event.StartTime),
+				Fix:     "make it an all-day event",
+				repair: func() error {
+					event.StartTime, event.EndTime = "all-day", ""
+					return d.rewrite(rel, name, rel, event)
+				},
+			})
+		case event.EndTime != "" && endErr != nil:
+			d.add(&Problem{
+				Path:    path,
+				Message: fmt.Sprintf("has an invalid end time %q", event.EndTime),
+				Fix:     "drop the end time",
+				repair: func() error {
+					event.EndTime = ""
+					return d.rewrite(rel, name, rel, event)
+				},
+			})
+		case event.EndTime != "" && end.Equal(start):
+			d.add(&Problem{
+				Path:    path,
+				Message: "ends when it starts",
+				Fix:     "drop the end time",
+				repair: func() error {
+					event.EndTime = ""
+					return d.rewrite(rel, name, rel, event)
+				},
+			})
+		case event.IsOvernight() && strings.HasPrefix(rel, "days"):
+			// The calendar reads these as ending the next day
+			date, _ := time.ParseInLocation(model.DateFormat, filepath.Base(rel), time.Local)
+			d.add(&Problem{
+				Path:    path,
+				Message: fmt.Sprintf("ends before it starts (%s-%s)", event.StartTime, event.EndTime),
+				Fix:     "make it an overnight event ending the next day",
+				repair: func() error {
+					event.SpanOvernight(date)
+					return d.rewrite(rel, name, "spans", event)
+				},
+			})
+		}
+	}
+
+	// Duplicate suffixes: "Standup~2" next to a "Standup" it copies, or
+	// without a "Standup" to tell apart from
+	if base := legacyCopy(d.root, rel, name, content); base != "" {
+		// Older versions added "_2" instead, which is also how the title
+		// "Standup 2" is written, so this may be an event of its own
+		d.add(&Problem{
+			Path:    path,
+			Message: fmt.Sprintf("may be a copy of %s saved by an older version; delete it if it isn't an event titled %q", base, event.Title),
+			Warning: true,
+		})
+	}
+	if base := filename(rel, event); name != base && strings.HasPrefix(name, base+"~") {
+		original, err := os.ReadFile(filepath.Join(d.root, rel, base))
+		switch {
+		case os.IsNotExist(err):
+			d.add(&Problem{
+				Path:    path,
+				Message: "has a duplicate suffix but no event shares its name",
+				Fix:     "rename it to " + base,
+				repair: func() error {
+					return os.Rename(filepath.Join(d.root, path), filepath.Join(d.root, rel, base))
+				},
+			})
+			d.checkID(path, event)
+		case err == nil && sameContent(original, content):
+			d.add(&Problem{
+				Path:    path,
+				Message: "is a copy of " + base,
+				Fix:     "move it to " + LostAndFound,
+				repair:  func() error { return d.moveToLostAndFound(path) },
+			})
+		default:
+			d.checkID(path, event)
+		}
+	} else {
+		d.checkID(path, event)
+	}
+
+	for _, category := range event.Categories {
+		if !d.knownCategory(category) {
+			key := strings.ToLower(category)
+			d.unknown[key] = append(d.unknown[key], path)
+		}
+	}
+}
+
+// checkID reports an event sharing its ID with an earlier one, which makes
+// edits to either change the wrong file
+func (d *doctor) checkID(path string, event *model.Event) {
+	if event.ID == "" {
+		return // given one when it is first loaded
+	}
+	first, seen := d.ids[event.ID]
+	if !seen {
+		d.ids[event.ID] = path
+		return
+	}
+	d.add(&Problem{
+		Path:    path,
+		Message: "has the same id as " + first,
+		Fix:     "give it a new id",
+		repair: func() error {
+			event.ID = model.NewID()
+			return storage.WriteFileAtomic(filepath.Join(d.root, path), []byte(event.FormatFileContent()), 0644)
+		},
+	})
+}
+
+func (d *doctor) knownCategory(name string) bool {
+	for _, cat := range d.cfg.Categories {
+		if strings.EqualFold(cat.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCategories reports each category used by events but missing from
+// the config
+func (d *doctor) checkCategories() {
+	var names []string
+	for name := range d.unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		files := d.unknown[name]
+		used := files[0]
+		if len(files) > 1 {
+			used = fmt.Sprintf("%s and %d more", files[0], len(files)-1)
+		}
+		d.add(&Problem{
+			Path:    "config.json",
+			Message: fmt.Sprintf("has no category %q, used by %s", name, used),
+			Fix:     fmt.Sprintf("add %q to the categories", name),
+			repair: func() error {
+				if d.knownCategory(name) {
+					return nil
+				}
+				d.cfg.Categories = append(d.cfg.Categories, config.Category{Name: name, Color: "#808080"})
+				return d.cfg.Save()
+			},
+		})
+	}
+}
+
+// rewrite replaces the event file name in the directory at rel by event,
+// written to the directory at to under the name its new times give it
+func (d *doctor) rewrite(rel, name, to string, event *model.Event) error {
+	dir := filepath.Join(d.root, to)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	old := filepath.Join(d.root, rel, name)
+	base := filename(to, event)
+	target := filepath.Join(dir, base)
+	for n := 2; target != old; n++ {
+		if _, err := os.Stat(target); os.IsNotExist(err) {
+			break
+		}
+		target = filepath.Join(dir, model.DuplicateFilename(base, n))
+	}
+	if err := storage.WriteFileAtomic(target, []byte(event.FormatFileContent()), 0644); err != nil {
+		return err
+	}
+	if target == old {
+		return nil
+	}
+	return os.Remove(old)
+}
+
+// moveToLostAndFound moves rel under LostAndFound
+func (d *doctor) moveToLostAndFound(rel string) error {
+	target := filepath.Join(d.root, LostAndFound, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(target); err == nil {
+		target += fmt.Sprintf("-%d", time.Now().Unix())
+	}
+	return os.Rename(filepath.Join(d.root, rel), target)
+}
+
+// parse reads an event file the way the directory at rel stores it
+func parse(rel, name, content string) (*model.Event, error) {
+	switch rel {
+	case "spans":
+		return model.ParseSpanFromFilename(name, content)
+	case "recurring":
+		return model.ParseSeriesFromFilename(name, content)
+	}
+	return model.ParseEventFromFilename(name, content)
+}
+
+// filename returns the name event is stored under in the directory at rel
+func filename(rel string, event *model.Event) string {
+	switch rel {
+	case "spans":
+		return event.SpanFilename()
+	case "recurring":
+		return event.SeriesFilename()
+	}
+	return event.GenerateFilename()
+}
+
+// legacyCopy returns base if name is "<base>_N", the way older versions
+// named the N-th event sharing a filename, and the file base in the
+// directory at rel holds the same content; "" otherwise
+func legacyCopy(root, rel, name string, content []byte) string {
+	idx := strings.LastIndex(name, "_")
+	if idx <= 0 {
+		return ""
+	}
+	n, err := strconv.Atoi(name[idx+1:])
+	if err != nil || n < 2 || strconv.Itoa(n) != name[idx+1:] {
+		return ""
+	}
+	base := name[:idx]
+	original, err := os.ReadFile(filepath.Join(root, rel, base))
+	if err != nil || !sameContent(original, content) {
+		return ""
+	}
+	return base
+}
+
+// sameContent reports whether two event files differ at most in their IDs
+func sameContent(a, b []byte) bool {
+	strip := func(data []byte) []byte {
+		var out [][]byte
+		for _, line := range bytes.Split(data, []byte("\n")) {
+			if !bytes.HasPrefix(line, []byte("id:")) {
+				out = append(out, line)
+			}
+		}
+		return bytes.Join(out, []byte("\n"))
+	}
+	return bytes.Equal(strip(a), strip(b))
+}
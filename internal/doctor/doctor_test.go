@@ -0,0 +1,57 @@
+package doctor
+
+import (
+	"bubblecal/internal/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLegacyDuplicates(t *testing.T) {
+	root := t.TempDir()
+	day := filepath.Join(root, "days", "2025-08-13")
+	if err := os.MkdirAll(day, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"0900-1000-Standup":   "id:a\nlocation:Room 4\n",
+		"0900-1000-Standup_2": "id:b\nlocation:Room 4\n", // a copy
+		"1100-Review":         "id:c\nlocation:Room 4\n",
+		"1100-Review_2":       "id:d\nlocation:Room 5\n", // another event
+		"1100-Review~2":       "id:e\nlocation:Room 6\n",
+		"1300-Lunch_2":        "id:f\n", // titled "Lunch 2"
+	} {
+		if err := os.WriteFile(filepath.Join(day, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	problems, err := Check(root, config.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "Review 2" and "Lunch 2" are events of their own; "Standup 2" may
+	// be too, so it is only reported
+	if len(problems) != 1 || filepath.Base(problems[0].Path) != "0900-1000-Standup_2" || !problems[0].Warning {
+		for _, p := range problems {
+			t.Logf("%s %s", p.Path, p.Message)
+		}
+		t.Fatalf("got %d problems, want a warning about 0900-1000-Standup_2", len(problems))
+	}
+	if err := problems[0].Repair(); err == nil {
+		t.Error("repaired a warning")
+	}
+
+	entries, _ := os.ReadDir(day)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	want := []string{"0900-1000-Standup", "0900-1000-Standup_2", "1100-Review", "1100-Review_2", "1100-Review~2", "1300-Lunch_2"}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,187 @@
+// Package export writes events in formats other calendars can import
+package export
+
+import (
+	"bubblecal/internal/model"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WriteICS writes the events loaded for a range of days, as returned by
+// Store.LoadRange, as an iCalendar (RFC 5545) file. Recurring events are
+// written as their individual occurrences, and multi-day events once.
+// Events with a time zone are written in UTC; floating times stay
+// floating.
+func WriteICS(w io.Writer, days map[string][]*model.Event) error {
+	var keys []string
+	for key := range days {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	b := &icsWriter{w: w}
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:-//bubblecal//bubblecal//EN")
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		for _, evt := range days[key] {
+			uid := eventUID(evt, key)
+			if seen[uid] {
+				continue
+			}
+			seen[uid] = true
+			if err := writeEvent(b, evt, key, uid, stamp); err != nil {
+				return fmt.Errorf("event %q on %s: %w", evt.Title, key, err)
+			}
+		}
+	}
+	b.line("END:VCALENDAR")
+	return b.err
+}
+
+// eventUID identifies an event, or one occurrence of a recurring event
+func eventUID(evt *model.Event, key string) string {
+	id := evt.ID
+	if id == "" {
+		id = model.EncodeFilenameTitle(evt.Title) + "-" + key
+	}
+	if evt.RecurrenceID != "" && !evt.IsOverride() {
+		id += "-" + evt.RecurrenceID
+	}
+	return id + "@bubblecal"
+}
+
+func writeEvent(b *icsWriter, evt *model.Event, key, uid, stamp string) error {
+	startDate := evt.StartDate
+	if startDate == "" {
+		startDate = key
+	}
+	endDate := evt.EndDate
+	if endDate == "" {
+		endDate = startDate
+	}
+
+	b.line("BEGIN:VEVENT")
+	b.line("UID:" + uid)
+	b.line("DTSTAMP:" + stamp)
+	if evt.IsAllDay() {
+		start, err := time.Parse(model.DateFormat, startDate)
+		if err != nil {
+			return err
+		}
+		end, err := time.Parse(model.DateFormat, endDate)
+		if err != nil {
+			return err
+		}
+		// DTEND is exclusive for dates
+		b.line("DTSTART;VALUE=DATE:" + start.Format("20060102"))
+		b.line("DTEND;VALUE=DATE:" + end.AddDate(0, 0, 1).Format("20060102"))
+	} else {
+		start, err := icsTime(evt, startDate, evt.StartTime)
+		if err != nil {
+			return err
+		}
+		b.line("DTSTART:" + start)
+		if evt.EndTime != "" {
+			end, err := icsTime(evt, endDate, evt.EndTime)
+			if err != nil {
+				return err
+			}
+			b.line("DTEND:" + end)
+		}
+	}
+
+	b.line("SUMMARY:" + escapeText(evt.Title))
+	if evt.Description != "" {
+		b.line("DESCRIPTION:" + escapeText(evt.Description))
+	}
+	if evt.Location != "" {
+		b.line("LOCATION:" + escapeText(evt.Location))
+	}
+	if evt.URL != "" {
+		b.line("URL:" + evt.URL)
+	}
+	for _, a := range evt.Attendees {
+		if strings.Contains(a, "@") {
+			b.line("ATTENDEE:mailto:" + a)
+		} else {
+			b.line(fmt.Sprintf("ATTENDEE;CN=%s:invalid:nomail", quoteParam(a)))
+		}
+	}
+	if len(evt.Categories) > 0 {
+		var cats []string
+		for _, c := range evt.Categories {
+			cats = append(cats, escapeText(c))
+		}
+		b.line("CATEGORIES:" + strings.Join(cats, ","))
+	}
+	if evt.Status != "" {
+		b.line("STATUS:" + strings.ToUpper(evt.Status))
+	}
+	b.line("END:VEVENT")
+	return nil
+}
+
+// icsTime formats a date and "15:04" time: in UTC if the event has a time
+// zone, as a floating local time otherwise
+func icsTime(evt *model.Event, date, clock string) (string, error) {
+	t, err := time.Parse(model.DateFormat+" 15:04", date+" "+clock)
+	if err != nil {
+		return "", err
+	}
+	loc, err := evt.Zone()
+	if err != nil {
+		return "", err
+	}
+	if loc == nil {
+		return t.Format("20060102T150405"), nil
+	}
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	return t.UTC().Format("20060102T150405Z"), nil
+}
+
+// escapeText escapes a TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// quoteParam quotes a parameter value that contains separators
+func quoteParam(s string) string {
+	s = strings.ReplaceAll(s, `"`, "'")
+	if strings.ContainsAny(s, ",:;") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// icsWriter writes CRLF-terminated lines folded at 75 octets and keeps
+// the first error
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (b *icsWriter) line(s string) {
+	if b.err != nil {
+		return
+	}
+	var out strings.Builder
+	limit := 75
+	for len(s) > limit {
+		// Don't split a UTF-8 sequence
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		out.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	out.WriteString(s + "\r\n")
+	_, b.err = io.WriteString(b.w, out.String())
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Event files hold a header block of "key:value" lines, then a blank line
+// and the description as free-form text:
+//
+//	id:4f2a9c1e8b7d6a50
+//	category:Work,Important
+//	location:Room 4
+//	url:https://meet.example.com/abc
+//	attendees:dana@example.com, lior@example.com
+//	status:confirmed
+//	x-color-override:#ff0000
+//
+//	Agenda for the quarterly review.
+//	Bring the numbers.
+//
+// Keys are case-insensitive. Keys bubblecal doesn't know, such as custom
+// "x-" keys, are kept in Event.Extra and written back unchanged. Older
+// files without a blank line and with a single "description:" line are
+// still read.
+
+// Property is a header key and its value
+type Property struct {
+	Key   string
+	Value string
+}
+
+// parseFileContent fills in the event from the contents of its file
+func (e *Event) parseFileContent(content string) error {
+	lines := strings.Split(content, "\n")
+	body := len(lines)
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			body = i + 1
+			break
+		}
+		key, value, ok := splitHeaderLine(line)
+		if !ok {
+			// Not a header line, so the body starts here
+			body = i
+			break
+		}
+		if err := e.setHeader(key, value); err != nil {
+			return err
+		}
+	}
+	if body < len(lines) {
+		if text := strings.TrimRight(strings.Join(lines[body:], "\n"), "\n\r\t "); text != "" {
+			e.Description = text
+		}
+	}
+	return nil
+}
+
+// splitHeaderLine splits "key:value"; keys are letters, digits and dashes
+func splitHeaderLine(line string) (string, string, bool) {
+	key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+	if !found || key == "" {
+		return "", "", false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return "", "", false
+		}
+	}
+	return key, strings.TrimSpace(value), true
+}
+
+// setHeader applies one header key to the event
+func (e *Event) setHeader(key, value string) error {
+	switch strings.ToLower(key) {
+	case "id":
+		e.ID = value
+	case "category":
+		e.Categories = ParseCategories(value)
+	case "description":
+		// Single-line descriptions written by older versions
+		e.Description = value
+	case "tz":
+		e.TimeZone = value
+	case "location":
+		e.Location = value
+	case "url":
+		e.URL = value
+	case "attendees":
+		e.Attendees = ParseCategories(value)
+	case "status":
+		e.Status = strings.ToLower(value)
+	case "rrule":
+		rule, err := ParseRecurrence(value)
+		if err != nil {
+			return err
+		}
+		e.Recurrence = rule
+	case "exdate":
+		for _, date := range strings.Split(value, ",") {
+			if date = strings.TrimSpace(date); date != "" {
+				e.ExDates = append(e.ExDates, date)
+			}
+		}
+	case "series":
+		e.SeriesID = value
+	case "recurrence-id":
+		e.RecurrenceID = value
+	default:
+		e.Extra = append(e.Extra, Property{Key: key, Value: value})
+	}
+	return nil
+}
+
+// FormatFileContent formats the event's content for saving to file
+func (e *Event) FormatFileContent() string {
+	var b strings.Builder
+	header := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s:%s\n", key, value)
+		}
+	}
+	fmt.Fprintf(&b, "id:%s\n", e.ID)
+	header("category", strings.Join(e.Categories, ","))
+	header("tz", e.TimeZone)
+	header("location", oneLine(e.Location))
+	header("url", oneLine(e.URL))
+	header("attendees", strings.Join(e.Attendees, ", "))
+	header("status", e.Status)
+	if e.Recurrence != nil {
+		header("rrule", e.Recurrence.String())
+	}
+	header("exdate", strings.Join(e.ExDates, ","))
+	if e.SeriesID != "" {
+		header("series", e.SeriesID)
+		header("recurrence-id", e.RecurrenceID)
+	}
+	for _, p := range e.Extra {
+		fmt.Fprintf(&b, "%s:%s\n", p.Key, oneLine(p.Value))
+	}
+	if e.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", e.Description)
+	}
+	return b.String()
+}
+
+// oneLine keeps a header value on its line
+func oneLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -1,17 +1,33 @@
 package model
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
 )
 
 type Event struct {
-	StartTime   string // "09:00", "all-day"
-	EndTime     string // "10:00", "" for single time or all-day
-	Title       string
-	Category    string // Single category field (was []string)
-	Description string // New field for event description
+	ID           string      // Persistent unique identifier, stored in the event file
+	StartTime    string      // "09:00", "all-day"
+	EndTime      string      // "10:00", "" for single time or all-day
+	Title        string
+	Categories   []string    // Category names; the first one is the primary category
+	Description  string      // New field for event description
+	StartDate    string      // "2006-01-02", first day of a multi-day event or recurring series
+	EndDate      string      // "2006-01-02", last day of a multi-day event ("" for single-day)
+	Recurrence   *Recurrence // Repeat rule for recurring series, nil otherwise
+	RecurrenceID string      // "2006-01-02", the date an expanded occurrence was generated for
+	ExDates      []string    // "2006-01-02" dates a series skips (cancelled or overridden)
+	SeriesID     string      // ID of the series a modified occurrence belongs to
+	TimeZone     string      // IANA zone the times are in, "" for floating times
+	Location     string      // Where the event takes place
+	URL          string      // Meeting or info link
+	Attendees    []string    // Names or email addresses
+	Status       string      // "tentative", "confirmed", "cancelled" or "" (unspecified)
+	Extra        []Property  // Header keys bubblecal doesn't know, kept as they are
+	DisplayZone  string      // zone the times were converted to for display, not stored
 }
 
 // ParseEventLine parses a line from a day file into an Event
@@ -31,7 +47,7 @@ func ParseEventLine(line string) (*Event, error) {
 		event.StartTime = "all-day"
 		event.EndTime = ""
 		remainder := strings.TrimPrefix(line, "all-day ")
-		event.Title, event.Category = extractTitleAndCategories(remainder)
+		event.Title, event.Categories = extractTitleAndCategories(remainder)
 		return event, nil
 	}
 
@@ -58,12 +74,12 @@ func ParseEventLine(line string) (*Event, error) {
 	}
 
 	// Extract title and categories from remainder
-	event.Title, event.Category = extractTitleAndCategories(remainder)
+	event.Title, event.Categories = extractTitleAndCategories(remainder)
 
 	return event, nil
 }
 
-func extractTitleAndCategories(text string) (string, string) {
+func extractTitleAndCategories(text string) (string, []string) {
 	// Look for categories in brackets at the end
 	if idx := strings.LastIndex(text, "["); idx != -1 {
 		title := strings.TrimSpace(text[:idx])
@@ -73,12 +89,22 @@ func extractTitleAndCategories(text string) (string, string) {
 		catPart = strings.TrimPrefix(catPart, "[")
 		catPart = strings.TrimSuffix(catPart, "]")
 		
-		// For now, join multiple categories with comma (legacy support)
-		return title, strings.TrimSpace(catPart)
+		return title, ParseCategories(catPart)
 	}
 	
 	// No categories found
-	return strings.TrimSpace(text), ""
+	return strings.TrimSpace(text), nil
+}
+
+// ParseCategories splits a comma-separated list of category names
+func ParseCategories(list string) []string {
+	var categories []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			categories = append(categories, name)
+		}
+	}
+	return categories
 }
 
 // FormatEventLine formats an Event back into a line for saving
@@ -94,13 +120,70 @@ func (e *Event) FormatEventLine() string {
 
 	result := fmt.Sprintf("%s %s", timePart, e.Title)
 	
-	if e.Category != "" {
-		result += fmt.Sprintf(" [%s]", e.Category)
+	if len(e.Categories) > 0 {
+		result += fmt.Sprintf(" [%s]", strings.Join(e.Categories, ","))
 	}
 	
 	return result
 }
 
+// NewID generates a new random event identifier
+func NewID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to a time-based ID if the system RNG is unavailable
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Clone returns a copy of the event
+func (e *Event) Clone() *Event {
+	c := *e
+	if e.Recurrence != nil {
+		c.Recurrence = e.Recurrence.Clone()
+	}
+	c.ExDates = append([]string(nil), e.ExDates...)
+	c.Categories = append([]string(nil), e.Categories...)
+	c.Attendees = append([]string(nil), e.Attendees...)
+	c.Extra = append([]Property(nil), e.Extra...)
+	return &c
+}
+
+// PrimaryCategory returns the category that gives the event its color,
+// or "" if it has none
+func (e *Event) PrimaryCategory() string {
+	if len(e.Categories) == 0 {
+		return ""
+	}
+	return e.Categories[0]
+}
+
+// Matches reports whether query appears, ignoring case, in the event's
+// title, description, location, URL, attendees or categories
+func (e *Event) Matches(query string) bool {
+	query = strings.ToLower(query)
+	fields := []string{e.Title, e.Description, e.Location, e.URL}
+	fields = append(fields, e.Attendees...)
+	fields = append(fields, e.Categories...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCategory reports whether the event is tagged with a category
+func (e *Event) HasCategory(name string) bool {
+	for _, c := range e.Categories {
+		if strings.EqualFold(c, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAllDay returns true if this is an all-day event
 func (e *Event) IsAllDay() bool {
 	return e.StartTime == "all-day"
@@ -119,18 +202,10 @@ func (e *Event) GetStartTime() (time.Time, error) {
 
 // GenerateFilename creates a filename for this event
 // Format: "HHMM-HHMM-title" or "allday-title"
+// The title is encoded with EncodeFilenameTitle so that
+// ParseEventFromFilename recovers it exactly.
 func (e *Event) GenerateFilename() string {
-	// Sanitize title for filename (replace spaces and special chars)
-	safeTitle := strings.ReplaceAll(e.Title, " ", "_")
-	safeTitle = strings.ReplaceAll(safeTitle, "/", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, ":", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "\\", "-")
-	safeTitle = strings.ReplaceAll(safeTitle, "?", "")
-	safeTitle = strings.ReplaceAll(safeTitle, "*", "")
-	safeTitle = strings.ReplaceAll(safeTitle, "\"", "")
-	safeTitle = strings.ReplaceAll(safeTitle, "<", "")
-	safeTitle = strings.ReplaceAll(safeTitle, ">", "")
-	safeTitle = strings.ReplaceAll(safeTitle, "|", "")
+	safeTitle := EncodeFilenameTitle(e.Title)
 	
 	if e.IsAllDay() {
 		return fmt.Sprintf("allday-%s", safeTitle)
@@ -142,6 +217,12 @@ func (e *Event) GenerateFilename() string {
 		end := strings.ReplaceAll(e.EndTime, ":", "")
 		return fmt.Sprintf("%s-%s-%s", start, end, safeTitle)
 	}
+	
+	// A title that starts like an end time ("1000-...") would be read back
+	// as one, so escape its first digit
+	if len(safeTitle) > 4 && isDigits(safeTitle[:4]) && safeTitle[4] == '-' {
+		safeTitle = fmt.Sprintf("%%%02X", safeTitle[0]) + safeTitle[1:]
+	}
 	return fmt.Sprintf("%s-%s", start, safeTitle)
 }
 
@@ -149,23 +230,17 @@ func (e *Event) GenerateFilename() string {
 func ParseEventFromFilename(filename string, content string) (*Event, error) {
 	event := &Event{}
 	
-	// Parse category and description from content
-	lines := strings.Split(content, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "category:") {
-			event.Category = strings.TrimSpace(strings.TrimPrefix(line, "category:"))
-		} else if strings.HasPrefix(line, "description:") {
-			event.Description = strings.TrimSpace(strings.TrimPrefix(line, "description:"))
-		}
+	// Parse the header block and body from content
+	if err := event.parseFileContent(content); err != nil {
+		return nil, err
 	}
 	
 	// Check if it's an all-day event
 	if strings.HasPrefix(filename, "allday-") {
 		event.StartTime = "all-day"
 		event.EndTime = ""
-		titlePart := strings.TrimPrefix(filename, "allday-")
-		event.Title = strings.ReplaceAll(titlePart, "_", " ")
+		titlePart, _ := splitDuplicateSuffix(strings.TrimPrefix(filename, "allday-"))
+		event.Title = DecodeFilenameTitle(titlePart)
 		return event, nil
 	}
 	
@@ -176,28 +251,25 @@ func ParseEventFromFilename(filename string, content string) (*Event, error) {
 	}
 	
 	// First part is start time
-	if len(parts[0]) == 4 {
+	if len(parts[0]) == 4 && isDigits(parts[0]) {
 		event.StartTime = fmt.Sprintf("%s:%s", parts[0][:2], parts[0][2:])
 	} else {
 		return nil, fmt.Errorf("invalid start time in filename: %s", parts[0])
 	}
 	
 	// Check if second part is end time or title
-	if len(parts[1]) == 4 && len(parts) > 2 {
+	var titlePart string
+	if len(parts[1]) == 4 && isDigits(parts[1]) && len(parts) > 2 {
 		// It's an end time
 		event.EndTime = fmt.Sprintf("%s:%s", parts[1][:2], parts[1][2:])
-		event.Title = strings.ReplaceAll(parts[2], "_", " ")
+		titlePart = parts[2]
 	} else {
 		// It's part of the title
 		event.EndTime = ""
-		titleParts := parts[1:]
-		event.Title = strings.ReplaceAll(strings.Join(titleParts, "-"), "_", " ")
+		titlePart = strings.Join(parts[1:], "-")
 	}
+	titlePart, _ = splitDuplicateSuffix(titlePart)
+	event.Title = DecodeFilenameTitle(titlePart)
 	
 	return event, nil
 }
-
-// FormatFileContent formats the event's content for saving to file
-func (e *Event) FormatFileContent() string {
-	return fmt.Sprintf("category:%s\ndescription:%s\n", e.Category, e.Description)
-}
\ No newline at end of file
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Titles are stored in filenames using a reversible encoding:
+//
+//   - a space becomes "_"
+//   - "_", "%", "~", path separators, characters that are invalid on
+//     common filesystems and control characters are percent-escaped
+//     as "%XX" (one escape per UTF-8 byte)
+//   - everything else, including non-ASCII letters, is kept as-is
+//
+// so "Team Standup" stays "Team_Standup" while "snake_case review"
+// becomes "snake%5Fcase_review".
+
+// filenameUnsafe lists the ASCII characters that are always escaped
+const filenameUnsafe = "_%~/\\:?*\"<>|"
+
+// duplicateSeparator separates a title from the counter added when two
+// events would otherwise share a filename ("Standup~2")
+const duplicateSeparator = "~"
+
+// EncodeFilenameTitle encodes a title for use in an event filename
+func EncodeFilenameTitle(title string) string {
+	var b strings.Builder
+	for i := 0; i < len(title); {
+		r, size := utf8.DecodeRuneInString(title[i:])
+		switch {
+		case r == ' ':
+			b.WriteByte('_')
+		case r == utf8.RuneError && size == 1,
+			r < 0x20 || r == 0x7f,
+			strings.ContainsRune(filenameUnsafe, r),
+			i == 0 && r == '.':
+			for j := i; j < i+size; j++ {
+				fmt.Fprintf(&b, "%%%02X", title[j])
+			}
+		default:
+			b.WriteString(title[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// DecodeFilenameTitle reverses EncodeFilenameTitle. Malformed escapes
+// are kept literally so older filenames still load.
+func DecodeFilenameTitle(encoded string) string {
+	var b []byte
+	for i := 0; i < len(encoded); i++ {
+		c := encoded[i]
+		switch {
+		case c == '_':
+			b = append(b, ' ')
+		case c == '%' && i+2 < len(encoded) && isHex(encoded[i+1]) && isHex(encoded[i+2]):
+			v, _ := strconv.ParseUint(encoded[i+1:i+3], 16, 8)
+			b = append(b, byte(v))
+			i += 2
+		default:
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// splitDuplicateSuffix strips a "~N" collision counter from an encoded
+// title and returns the remaining title and the counter (0 if none)
+func splitDuplicateSuffix(encoded string) (string, int) {
+	idx := strings.LastIndex(encoded, duplicateSeparator)
+	if idx == -1 {
+		return encoded, 0
+	}
+	n, err := strconv.Atoi(encoded[idx+1:])
+	if err != nil || n < 2 {
+		return encoded, 0
+	}
+	return encoded[:idx], n
+}
+
+// DuplicateFilename returns the filename used for the n-th event that
+// would otherwise share filename
+func DuplicateFilename(filename string, n int) string {
+	return fmt.Sprintf("%s%s%d", filename, duplicateSeparator, n)
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Slot is a stretch of free time on one day, as "15:04" times
+type Slot struct {
+	Start string
+	End   string
+}
+
+// FreeSlots returns the free stretches of time between from and to
+// ("15:04") on date. Timed events take up time, tentative ones included;
+// cancelled events, all-day events and events without an end time don't.
+func FreeSlots(events []*Event, date time.Time, from, to string) []Slot {
+	start, ok1 := clockMinutes(from)
+	end, ok2 := clockMinutes(to)
+	if !ok1 || !ok2 || end <= start {
+		return nil
+	}
+
+	type interval struct{ from, to int }
+	var busy []interval
+	for _, evt := range events {
+		if evt.IsCancelled() {
+			continue
+		}
+		seg := evt.SegmentOn(date)
+		if seg.AllDay {
+			// Timed events running over several days fill the days between
+			if !evt.IsAllDay() {
+				busy = append(busy, interval{0, 24 * 60})
+			}
+			continue
+		}
+		s, ok := clockMinutes(seg.Start)
+		if !ok {
+			continue
+		}
+		e := 24 * 60
+		if !seg.ContinuesAfter && seg.End != "00:00" {
+			if e, ok = clockMinutes(seg.End); !ok || e <= s {
+				continue
+			}
+		}
+		busy = append(busy, interval{s, e})
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].from < busy[j].from })
+
+	var slots []Slot
+	for _, b := range busy {
+		if b.from > start {
+			slots = append(slots, Slot{formatMinutes(start), formatMinutes(min(b.from, end))})
+		}
+		if b.to > start {
+			start = b.to
+		}
+		if start >= end {
+			return slots
+		}
+	}
+	return append(slots, Slot{formatMinutes(start), formatMinutes(end)})
+}
+
+// Duration returns the length of the slot
+func (s Slot) Duration() time.Duration {
+	start, _ := clockMinutes(s.Start)
+	end, _ := clockMinutes(s.End)
+	return time.Duration(end-start) * time.Minute
+}
+
+func clockMinutes(clock string) (int, bool) {
+	if clock == "24:00" {
+		return 24 * 60, true
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+func formatMinutes(m int) string {
+	if m >= 24*60 {
+		return "24:00"
+	}
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
@@ -0,0 +1,489 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recurrence is a subset of an RFC 5545 RRULE:
+//
+//	FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10
+//	FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231
+//
+// BYDAY entries may carry an ordinal for MONTHLY and YEARLY rules
+// ("1MO" is the first Monday, "-1FR" the last Friday of the month).
+// YEARLY rules repeat in the month the series starts in.
+type Recurrence struct {
+	Freq     string       // "DAILY", "WEEKLY", "MONTHLY" or "YEARLY"
+	Interval int          // every Interval periods, at least 1
+	ByDay    []WeekdayNum // restricts occurrences to these weekdays
+	Count    int          // total number of occurrences, 0 for no limit
+	Until    string       // "2006-01-02", last possible occurrence date
+}
+
+// WeekdayNum is one BYDAY entry. Ordinal is 0 for every such weekday.
+type WeekdayNum struct {
+	Ordinal int
+	Day     time.Weekday
+}
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// maxRecurrenceDays bounds how far ahead a COUNT rule is searched
+const maxRecurrenceDays = 100 * 366
+
+// lastOccurrences remembers the last occurrence of COUNT rules by rule
+// and start, since finding it can take a scan of years of days and
+// OccursOn needs it for every date it is asked about
+var lastOccurrences = struct {
+	sync.Mutex
+	dates map[string]time.Time // zero if the series has no occurrence
+}{dates: make(map[string]time.Time)}
+
+// maxCachedRules bounds lastOccurrences; it starts afresh when full
+const maxCachedRules = 1000
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrence parses an RRULE value such as "FREQ=DAILY;COUNT=5".
+// A leading "RRULE:" is accepted.
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.TrimSpace(rule)
+	rule = strings.TrimPrefix(strings.ToUpper(rule), "RRULE:")
+	if rule == "" {
+		return nil, fmt.Errorf("empty recurrence rule")
+	}
+
+	r := &Recurrence{Interval: 1}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence part: %s", part)
+		}
+		switch key {
+		case "FREQ":
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+				r.Freq = value
+			default:
+				return nil, fmt.Errorf("unsupported frequency: %s", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid interval: %s", value)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid count: %s", value)
+			}
+			r.Count = n
+		case "UNTIL":
+			// Only the date matters: 20261231 or 20261231T235959Z
+			if len(value) < 8 {
+				return nil, fmt.Errorf("invalid until: %s", value)
+			}
+			until, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("invalid until: %s", value)
+			}
+			r.Until = until.Format(DateFormat)
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				wd, err := parseWeekdayNum(code)
+				if err != nil {
+					return nil, err
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		case "WKST":
+			// Weeks always start on Monday, the RFC 5545 default
+		default:
+			return nil, fmt.Errorf("unsupported recurrence part: %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("recurrence rule needs FREQ")
+	}
+	if r.Count > 0 && r.Until != "" {
+		return nil, fmt.Errorf("recurrence rule can't have both COUNT and UNTIL")
+	}
+	for _, wd := range r.ByDay {
+		if wd.Ordinal != 0 && r.Freq != FreqMonthly && r.Freq != FreqYearly {
+			return nil, fmt.Errorf("BYDAY ordinals need a MONTHLY or YEARLY rule")
+		}
+	}
+	return r, nil
+}
+
+func parseWeekdayNum(code string) (WeekdayNum, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday: %s", code)
+	}
+	day := -1
+	for i, c := range weekdayCodes {
+		if strings.HasSuffix(code, c) {
+			day = i
+			break
+		}
+	}
+	if day == -1 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday: %s", code)
+	}
+
+	wd := WeekdayNum{Day: time.Weekday(day)}
+	if prefix := code[:len(code)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid weekday: %s", code)
+		}
+		wd.Ordinal = n
+	}
+	return wd, nil
+}
+
+// String formats the rule as an RRULE value
+func (r *Recurrence) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, wd := range r.ByDay {
+			code := weekdayCodes[wd.Day]
+			if wd.Ordinal != 0 {
+				code = strconv.Itoa(wd.Ordinal) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	if r.Until != "" {
+		parts = append(parts, "UNTIL="+strings.ReplaceAll(r.Until, "-", ""))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe returns a short human readable form ("Every 2 weeks on Mon, Thu")
+func (r *Recurrence) Describe() string {
+	units := map[string]string{FreqDaily: "day", FreqWeekly: "week", FreqMonthly: "month", FreqYearly: "year"}
+	desc := "Every " + units[r.Freq]
+	if r.Interval > 1 {
+		desc = fmt.Sprintf("Every %d %ss", r.Interval, units[r.Freq])
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, wd := range r.ByDay {
+			day := wd.Day.String()[:3]
+			switch {
+			case wd.Ordinal == -1:
+				day = "last " + day
+			case wd.Ordinal < 0:
+				day = fmt.Sprintf("%s %d from last", day, -wd.Ordinal)
+			case wd.Ordinal > 0:
+				day = fmt.Sprintf("%s %s", ordinal(wd.Ordinal), day)
+			}
+			days = append(days, day)
+		}
+		desc += " on " + strings.Join(days, ", ")
+	}
+	if r.Count > 0 {
+		desc += fmt.Sprintf(", %d times", r.Count)
+	}
+	if r.Until != "" {
+		desc += ", until " + r.Until
+	}
+	return desc
+}
+
+func ordinal(n int) string {
+	switch n {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	return fmt.Sprintf("%dth", n)
+}
+
+// Clone returns a deep copy of the rule
+func (r *Recurrence) Clone() *Recurrence {
+	c := *r
+	c.ByDay = append([]WeekdayNum(nil), r.ByDay...)
+	return &c
+}
+
+// OccursOn reports whether a series starting on start has an occurrence
+// on date
+func (r *Recurrence) OccursOn(start, date time.Time) bool {
+	start, date = civilDate(start), civilDate(date)
+	if date.Before(start) {
+		return false
+	}
+	if r.Until != "" && date.Format(DateFormat) > r.Until {
+		return false
+	}
+	if !r.matches(start, date) {
+		return false
+	}
+	if r.Count > 0 {
+		last, ok := r.lastOccurrence(start)
+		return ok && !date.After(last)
+	}
+	return true
+}
+
+// Occurrences returns the occurrence dates of a series starting on start
+// that fall between from and to inclusive
+func (r *Recurrence) Occurrences(start, from, to time.Time) []time.Time {
+	from, to = civilDate(from), civilDate(to)
+	var dates []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if r.OccursOn(start, d) {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+// lastOccurrence returns the COUNT-th occurrence of a series, or the
+// last one before UNTIL or maxRecurrenceDays
+func (r *Recurrence) lastOccurrence(start time.Time) (time.Time, bool) {
+	key := r.String() + "@" + start.Format(DateFormat)
+	lastOccurrences.Lock()
+	last, ok := lastOccurrences.dates[key]
+	lastOccurrences.Unlock()
+	if !ok {
+		last = r.findLastOccurrence(start)
+		lastOccurrences.Lock()
+		if len(lastOccurrences.dates) >= maxCachedRules {
+			lastOccurrences.dates = make(map[string]time.Time)
+		}
+		lastOccurrences.dates[key] = last
+		lastOccurrences.Unlock()
+	}
+	return last, !last.IsZero()
+}
+
+// findLastOccurrence scans for what lastOccurrence returns, or the zero
+// time if there is no occurrence
+func (r *Recurrence) findLastOccurrence(start time.Time) time.Time {
+	var last time.Time
+	n := 0
+	for i := 0; i < maxRecurrenceDays; i++ {
+		d := start.AddDate(0, 0, i)
+		if r.Until != "" && d.Format(DateFormat) > r.Until {
+			break
+		}
+		if r.matches(start, d) {
+			last = d
+			n++
+			if n == r.Count {
+				break
+			}
+		}
+	}
+	return last
+}
+
+// matches applies FREQ, INTERVAL and BYDAY, ignoring COUNT and UNTIL.
+// Both dates are civil dates with date not before start.
+func (r *Recurrence) matches(start, date time.Time) bool {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch r.Freq {
+	case FreqDaily:
+		days := int(date.Sub(start).Hours() / 24)
+		if days%interval != 0 {
+			return false
+		}
+		return len(r.ByDay) == 0 || r.matchesWeekday(date)
+
+	case FreqWeekly:
+		weeks := int(weekStart(date).Sub(weekStart(start)).Hours() / 24 / 7)
+		if weeks%interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return date.Weekday() == start.Weekday()
+		}
+		return r.matchesWeekday(date)
+
+	case FreqMonthly:
+		months := (date.Year()-start.Year())*12 + int(date.Month()) - int(start.Month())
+		if months%interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return date.Day() == start.Day()
+		}
+		return r.matchesWeekday(date)
+
+	case FreqYearly:
+		years := date.Year() - start.Year()
+		if years%interval != 0 || date.Month() != start.Month() {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return date.Day() == start.Day()
+		}
+		return r.matchesWeekday(date)
+	}
+	return false
+}
+
+// matchesWeekday checks date against BYDAY, with ordinals counted within
+// the date's month
+func (r *Recurrence) matchesWeekday(date time.Time) bool {
+	for _, wd := range r.ByDay {
+		if date.Weekday() != wd.Day {
+			continue
+		}
+		switch {
+		case wd.Ordinal == 0:
+			return true
+		case wd.Ordinal > 0 && (date.Day()-1)/7+1 == wd.Ordinal:
+			return true
+		case wd.Ordinal < 0:
+			daysInMonth := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+			if (daysInMonth-date.Day())/7+1 == -wd.Ordinal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// civilDate drops the time of day and zone so date arithmetic isn't
+// affected by DST changes
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart returns the Monday of a civil date's week
+func weekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+// IsRecurring returns true for recurring series and their occurrences
+func (e *Event) IsRecurring() bool {
+	return e.Recurrence != nil
+}
+
+// IsOverride returns true for a modified occurrence stored on its own
+func (e *Event) IsOverride() bool {
+	return e.SeriesID != ""
+}
+
+// InSeries returns true for anything belonging to a recurring series:
+// the series, its occurrences and its overrides
+func (e *Event) InSeries() bool {
+	return e.IsRecurring() || e.IsOverride()
+}
+
+// SeriesKey returns the ID of the series an event belongs to
+func (e *Event) SeriesKey() string {
+	if e.IsOverride() {
+		return e.SeriesID
+	}
+	return e.ID
+}
+
+// IsExcluded reports whether a series skips the occurrence starting on date
+func (e *Event) IsExcluded(date time.Time) bool {
+	key := date.Format(DateFormat)
+	for _, ex := range e.ExDates {
+		if ex == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SeriesStart returns the first date of a recurring series
+func (e *Event) SeriesStart() (time.Time, error) {
+	return time.ParseInLocation(DateFormat, e.StartDate, time.Local)
+}
+
+// OccurrenceOn returns the occurrence of a recurring series that covers
+// date, or nil. A multi-day series covers the days after each start too.
+func (e *Event) OccurrenceOn(date time.Time) *Event {
+	start, err := e.SeriesStart()
+	if err != nil || e.Recurrence == nil {
+		return nil
+	}
+
+	length := e.SpanDays()
+	for back := 0; back < length; back++ {
+		occStart := date.AddDate(0, 0, -back)
+		if !e.Recurrence.OccursOn(start, occStart) || e.IsExcluded(occStart) {
+			continue
+		}
+		occ := e.Clone()
+		occ.ExDates = nil
+		occ.RecurrenceID = occStart.Format(DateFormat)
+		occ.StartDate = occ.RecurrenceID
+		if e.IsMultiDay() {
+			occ.EndDate = occStart.AddDate(0, 0, length-1).Format(DateFormat)
+		}
+		return occ
+	}
+	return nil
+}
+
+// SeriesFilename creates the filename of a recurring series or one of its
+// overrides, which starts with its first date: "2025-08-11-0930-0945-Standup"
+func (e *Event) SeriesFilename() string {
+	if e.IsMultiDay() {
+		return e.SpanFilename()
+	}
+	return fmt.Sprintf("%s-%s", e.StartDate, e.GenerateFilename())
+}
+
+// ParseSeriesFromFilename parses a series or override written by SeriesFilename
+func ParseSeriesFromFilename(filename string, content string) (*Event, error) {
+	if len(filename) > 10 && filename[10] == '_' {
+		return ParseSpanFromFilename(filename, content)
+	}
+
+	// "YYYY-MM-DD-" is 11 characters
+	if len(filename) < 11 || filename[10] != '-' {
+		return nil, fmt.Errorf("invalid series filename format: %s", filename)
+	}
+	startDate := filename[:10]
+	if _, err := time.Parse(DateFormat, startDate); err != nil {
+		return nil, fmt.Errorf("invalid date in series filename: %s", startDate)
+	}
+
+	event, err := ParseEventFromFilename(filename[11:], content)
+	if err != nil {
+		return nil, err
+	}
+	event.StartDate = startDate
+	return event, nil
+}
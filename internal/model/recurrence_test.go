@@ -0,0 +1,98 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		start    string
+		from, to string
+		want     string
+	}{
+		{"last friday", "FREQ=MONTHLY;BYDAY=-1FR", "2025-01-01", "2025-01-01", "2025-04-30",
+			"2025-01-31 2025-02-28 2025-03-28 2025-04-25"},
+		{"second tuesday", "FREQ=MONTHLY;BYDAY=2TU", "2025-01-01", "2025-01-01", "2025-03-31",
+			"2025-01-14 2025-02-11 2025-03-11"},
+		{"count", "FREQ=DAILY;COUNT=3", "2025-08-13", "2025-08-01", "2025-08-31",
+			"2025-08-13 2025-08-14 2025-08-15"},
+		{"count of weekdays", "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4", "2025-08-11", "2025-08-01", "2025-09-30",
+			"2025-08-11 2025-08-13 2025-08-18 2025-08-20"},
+		{"count of last fridays", "FREQ=MONTHLY;BYDAY=-1FR;COUNT=2", "2025-01-01", "2025-01-01", "2025-12-31",
+			"2025-01-31 2025-02-28"},
+		{"until", "FREQ=WEEKLY;UNTIL=20250901", "2025-08-13", "2025-08-01", "2025-09-30",
+			"2025-08-13 2025-08-20 2025-08-27"},
+		{"until the last occurrence", "FREQ=WEEKLY;UNTIL=20250827", "2025-08-13", "2025-08-01", "2025-09-30",
+			"2025-08-13 2025-08-20 2025-08-27"},
+		{"every third day", "FREQ=DAILY;INTERVAL=3", "2025-08-13", "2025-08-10", "2025-08-22",
+			"2025-08-13 2025-08-16 2025-08-19 2025-08-22"},
+		{"every other week", "FREQ=WEEKLY;INTERVAL=2", "2025-08-13", "2025-08-01", "2025-09-15",
+			"2025-08-13 2025-08-27 2025-09-10"},
+		{"months with a 31st", "FREQ=MONTHLY;COUNT=3", "2025-01-31", "2025-01-01", "2025-12-31",
+			"2025-01-31 2025-03-31 2025-05-31"},
+		{"every other month", "FREQ=MONTHLY;INTERVAL=2;COUNT=3", "2025-01-15", "2025-01-01", "2025-12-31",
+			"2025-01-15 2025-03-15 2025-05-15"},
+		{"yearly", "FREQ=YEARLY", "2024-08-13", "2024-01-01", "2026-12-31",
+			"2024-08-13 2025-08-13 2026-08-13"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, d := range r.Occurrences(day(tt.start), day(tt.from), day(tt.to)) {
+				got = append(got, d.Format(DateFormat))
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("got %v, want %s", got, tt.want)
+			}
+			// Asking again gives the same answer from the cache
+			if again := r.Occurrences(day(tt.start), day(tt.from), day(tt.to)); len(again) != len(got) {
+				t.Errorf("got %d occurrences the second time, want %d", len(again), len(got))
+			}
+		})
+	}
+}
+
+func TestCountRuleChangedAfterUse(t *testing.T) {
+	r, _ := ParseRecurrence("FREQ=DAILY;COUNT=3")
+	if !r.OccursOn(day("2025-08-13"), day("2025-08-15")) {
+		t.Fatal("the third day isn't an occurrence")
+	}
+	// Ending the series earlier, as editing this and following events does
+	shorter := r.Clone()
+	shorter.Count = 2
+	if shorter.OccursOn(day("2025-08-13"), day("2025-08-15")) {
+		t.Error("the shortened rule still occurs on the third day")
+	}
+}
+
+func TestOccurrenceOnSkipsExDates(t *testing.T) {
+	r, _ := ParseRecurrence("FREQ=DAILY")
+	series := &Event{ID: "s", StartTime: "09:00", Title: "Standup", StartDate: "2025-08-13", Recurrence: r,
+		ExDates: []string{"2025-08-14"}}
+	tests := []struct {
+		date string
+		want bool
+	}{
+		{"2025-08-12", false}, // before the series
+		{"2025-08-13", true},
+		{"2025-08-14", false}, // excluded
+		{"2025-08-15", true},
+	}
+	for _, tt := range tests {
+		occ := series.OccurrenceOn(day(tt.date))
+		if (occ != nil) != tt.want {
+			t.Errorf("%s: got %v, want an occurrence: %v", tt.date, occ, tt.want)
+			continue
+		}
+		if occ != nil && (occ.RecurrenceID != tt.date || occ.ExDates != nil) {
+			t.Errorf("%s: got occurrence %s with exdates %v", tt.date, occ.RecurrenceID, occ.ExDates)
+		}
+	}
+}
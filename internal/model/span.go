@@ -0,0 +1,198 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateFormat is the layout used for StartDate/EndDate and day directories
+const DateFormat = "2006-01-02"
+
+// Segment describes the part of an event that falls on a single day
+type Segment struct {
+	Start           string // "HH:MM", empty for all-day segments
+	End             string // "HH:MM", empty if open or until midnight
+	AllDay          bool   // covers the whole day
+	ContinuesBefore bool   // the event started on an earlier day
+	ContinuesAfter  bool   // the event goes on past this day
+}
+
+// Label formats the segment's time for agenda-style lists
+func (s Segment) Label() string {
+	switch {
+	case s.AllDay:
+		return "All day"
+	case s.ContinuesAfter:
+		return s.Start + "→"
+	case s.ContinuesBefore:
+		return "→" + s.End
+	case s.End != "":
+		return fmt.Sprintf("%s-%s", s.Start, s.End)
+	default:
+		return s.Start
+	}
+}
+
+// HourRange returns the first and last hour of the day the segment
+// occupies. ok is false for all-day segments or unparsable times.
+func (s Segment) HourRange() (first, last int, ok bool) {
+	if s.AllDay {
+		return 0, 0, false
+	}
+	var min int
+	if _, err := fmt.Sscanf(s.Start, "%d:%d", &first, &min); err != nil {
+		return 0, 0, false
+	}
+	last = first
+	switch {
+	case s.ContinuesAfter:
+		last = 23
+	case s.End != "":
+		var endHour, endMin int
+		if _, err := fmt.Sscanf(s.End, "%d:%d", &endHour, &endMin); err == nil {
+			if endHour == 0 && endMin == 0 {
+				endHour = 24 // ends at midnight
+			}
+			last = endHour
+			if endMin == 0 {
+				last--
+			}
+			if last < first {
+				last = first
+			}
+		}
+	}
+	return first, last, true
+}
+
+// IsMultiDay returns true if the event spans more than one date
+func (e *Event) IsMultiDay() bool {
+	return e.EndDate != "" && e.EndDate != e.StartDate
+}
+
+// Dates returns the first and last date of a multi-day event
+func (e *Event) Dates() (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(DateFormat, e.StartDate, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := time.ParseInLocation(DateFormat, e.EndDate, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+	}
+	return start, end, nil
+}
+
+// Covers reports whether a multi-day event includes date
+func (e *Event) Covers(date time.Time) bool {
+	if !e.IsMultiDay() {
+		return false
+	}
+	key := date.Format(DateFormat)
+	return key >= e.StartDate && key <= e.EndDate
+}
+
+// SegmentOn returns the part of the event shown on date. For single-day
+// events it simply reflects StartTime/EndTime.
+func (e *Event) SegmentOn(date time.Time) Segment {
+	if !e.IsMultiDay() {
+		if e.IsAllDay() {
+			return Segment{AllDay: true}
+		}
+		return Segment{Start: e.StartTime, End: e.EndTime}
+	}
+
+	key := date.Format(DateFormat)
+	first := key == e.StartDate
+	last := key == e.EndDate
+	seg := Segment{ContinuesBefore: !first, ContinuesAfter: !last}
+
+	switch {
+	case e.IsAllDay() || (!first && !last):
+		seg.AllDay = true
+	case first:
+		seg.Start = e.StartTime
+	default:
+		seg.Start = "00:00"
+		seg.End = e.EndTime
+	}
+	return seg
+}
+
+// IsOvernight reports whether a timed event ends on the day after it
+// starts, less than a day later ("22:00-02:00"). Events saved as a single
+// day with an end time before the start time count too, except for an end
+// of "00:00", which is midnight at the end of the start day.
+func (e *Event) IsOvernight() bool {
+	if e.IsAllDay() || e.EndTime == "" {
+		return false
+	}
+	start, err1 := time.Parse("15:04", e.StartTime)
+	end, err2 := time.Parse("15:04", e.EndTime)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	if e.IsMultiDay() {
+		return e.SpanDays() == 2 && !end.After(start)
+	}
+	return end.Before(start) && e.EndTime != "00:00"
+}
+
+// SpanOvernight turns a single-day overnight event starting on date into
+// a two-day event, so it shows up on both days
+func (e *Event) SpanOvernight(date time.Time) {
+	if e.IsMultiDay() || !e.IsOvernight() {
+		return
+	}
+	start := date
+	if e.StartDate != "" {
+		if d, err := time.ParseInLocation(DateFormat, e.StartDate, time.Local); err == nil {
+			start = d
+		}
+	}
+	e.StartDate = start.Format(DateFormat)
+	e.EndDate = start.AddDate(0, 0, 1).Format(DateFormat)
+}
+
+// SpanDays returns how many dates a multi-day event covers (1 otherwise)
+func (e *Event) SpanDays() int {
+	if !e.IsMultiDay() {
+		return 1
+	}
+	start, end, err := e.Dates()
+	if err != nil {
+		return 1
+	}
+	return int(end.Sub(start).Hours()/24+0.5) + 1
+}
+
+// SpanFilename creates the filename of a multi-day event:
+// "2025-08-14_2025-08-17-allday-Vacation"
+func (e *Event) SpanFilename() string {
+	return fmt.Sprintf("%s_%s-%s", e.StartDate, e.EndDate, e.GenerateFilename())
+}
+
+// ParseSpanFromFilename parses a multi-day event written by SpanFilename
+func ParseSpanFromFilename(filename string, content string) (*Event, error) {
+	// "YYYY-MM-DD_YYYY-MM-DD-" is 22 characters
+	if len(filename) < 22 || filename[10] != '_' || filename[21] != '-' {
+		return nil, fmt.Errorf("invalid span filename format: %s", filename)
+	}
+	startDate, endDate := filename[:10], filename[11:21]
+	for _, d := range []string{startDate, endDate} {
+		if _, err := time.Parse(DateFormat, d); err != nil {
+			return nil, fmt.Errorf("invalid date in span filename: %s", d)
+		}
+	}
+	if endDate < startDate {
+		return nil, fmt.Errorf("span ends before it starts: %s", filename)
+	}
+
+	event, err := ParseEventFromFilename(filename[22:], content)
+	if err != nil {
+		return nil, err
+	}
+	event.StartDate = startDate
+	event.EndDate = endDate
+	return event, nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestIsOvernight(t *testing.T) {
+	tests := []struct {
+		start, end string
+		want       bool
+	}{
+		{"22:00", "02:00", true},
+		{"23:30", "00:30", true},
+		{"22:00", "00:00", false}, // ends at midnight, on the same day
+		{"00:00", "00:00", false},
+		{"09:00", "10:00", false},
+		{"09:00", "", false},
+	}
+	for _, tt := range tests {
+		e := &Event{StartTime: tt.start, EndTime: tt.end}
+		if got := e.IsOvernight(); got != tt.want {
+			t.Errorf("%s-%s: IsOvernight() = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestEndingAtMidnightStaysOnOneDay(t *testing.T) {
+	e := &Event{StartTime: "22:00", EndTime: "00:00", Title: "Late show"}
+	e.SpanOvernight(day("2025-08-13"))
+	if e.IsMultiDay() {
+		t.Fatalf("spanned %s..%s", e.StartDate, e.EndDate)
+	}
+	if first, last, ok := e.SegmentOn(day("2025-08-13")).HourRange(); !ok || first != 22 || last != 23 {
+		t.Errorf("HourRange() = %d, %d, %v, want 22, 23", first, last, ok)
+	}
+	slots := FreeSlots([]*Event{e}, day("2025-08-13"), "20:00", "24:00")
+	if len(slots) != 1 || slots[0] != (Slot{"20:00", "22:00"}) {
+		t.Errorf("free %v, want 20:00-22:00", slots)
+	}
+}
@@ -0,0 +1,34 @@
+package model
+
+// Event statuses, as stored in the status header key
+const (
+	StatusTentative = "tentative"
+	StatusConfirmed = "confirmed"
+	StatusCancelled = "cancelled"
+)
+
+// IsTentative reports whether the event isn't confirmed yet
+func (e *Event) IsTentative() bool {
+	return e.Status == StatusTentative
+}
+
+// IsCancelled reports whether the event was called off. Cancelled events
+// are still shown but don't take up any time.
+func (e *Event) IsCancelled() bool {
+	return e.Status == StatusCancelled
+}
+
+// NextStatus returns the status that follows the event's one when
+// cycling through them: unspecified, tentative, confirmed, cancelled and
+// back to unspecified
+func (e *Event) NextStatus() string {
+	switch e.Status {
+	case "":
+		return StatusTentative
+	case StatusTentative:
+		return StatusConfirmed
+	case StatusConfirmed:
+		return StatusCancelled
+	}
+	return ""
+}
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Task is a todo with a due date, listed in the agenda next to the
+// events of its day. Task files use the same header and body layout as
+// event files:
+//
+//	title:Send the quarterly report
+//	due:2025-08-15 17:00
+//	priority:high
+//	category:Work
+//	done:false
+//
+//	Numbers are in the shared folder.
+type Task struct {
+	ID       string // Persistent unique identifier, also part of the filename
+	Title    string
+	Due      string // "2006-01-02"
+	DueTime  string // "15:04", "" if the task is due any time that day
+	Priority string // "high", "medium", "low" or ""
+	Done     bool
+	Category string
+	Notes    string
+}
+
+// Task priorities
+const (
+	PriorityHigh   = "high"
+	PriorityMedium = "medium"
+	PriorityLow    = "low"
+)
+
+// Priorities lists the priorities from most to least urgent
+var Priorities = []string{PriorityHigh, PriorityMedium, PriorityLow, ""}
+
+// priorityRank orders priorities, most urgent first
+func priorityRank(p string) int {
+	for i, q := range Priorities {
+		if p == q {
+			return i
+		}
+	}
+	return len(Priorities)
+}
+
+// ParseTask parses the contents of a task file
+func ParseTask(content string) (*Task, error) {
+	t := &Task{}
+	lines := strings.Split(content, "\n")
+	body := len(lines)
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			body = i + 1
+			break
+		}
+		key, value, ok := splitHeaderLine(line)
+		if !ok {
+			body = i
+			break
+		}
+		switch strings.ToLower(key) {
+		case "id":
+			t.ID = value
+		case "title":
+			t.Title = value
+		case "due":
+			date, clock, _ := strings.Cut(value, " ")
+			t.Due = date
+			t.DueTime = strings.TrimSpace(clock)
+		case "priority":
+			t.Priority = strings.ToLower(value)
+		case "category":
+			t.Category = value
+		case "done":
+			t.Done = value == "true" || value == "yes" || value == "x"
+		}
+	}
+	if body < len(lines) {
+		t.Notes = strings.TrimRight(strings.Join(lines[body:], "\n"), "\n\r\t ")
+	}
+
+	if t.Title == "" {
+		return nil, fmt.Errorf("task has no title")
+	}
+	if _, err := time.Parse(DateFormat, t.Due); err != nil {
+		return nil, fmt.Errorf("invalid due date %q", t.Due)
+	}
+	if t.DueTime != "" {
+		if _, err := time.Parse("15:04", t.DueTime); err != nil {
+			return nil, fmt.Errorf("invalid due time %q", t.DueTime)
+		}
+	}
+	return t, nil
+}
+
+// FormatFileContent formats the task for its file
+func (t *Task) FormatFileContent() string {
+	var b strings.Builder
+	header := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s:%s\n", key, oneLine(value))
+		}
+	}
+	header("id", t.ID)
+	header("title", t.Title)
+	header("due", strings.TrimSpace(t.Due+" "+t.DueTime))
+	header("priority", t.Priority)
+	header("category", t.Category)
+	header("done", fmt.Sprint(t.Done))
+	if t.Notes != "" {
+		b.WriteString("\n" + t.Notes + "\n")
+	}
+	return b.String()
+}
+
+// Filename returns the name of the task's file
+func (t *Task) Filename() string {
+	return t.ID + "-" + EncodeFilenameTitle(t.Title)
+}
+
+// Clone returns a copy of the task
+func (t *Task) Clone() *Task {
+	c := *t
+	return &c
+}
+
+// IsOverdue reports whether the task is unfinished and was due before
+// today
+func (t *Task) IsOverdue(today time.Time) bool {
+	return !t.Done && t.Due < today.Format(DateFormat)
+}
+
+// ShowsOn reports whether the task is listed on date: on its due date,
+// or on today's date instead while it is overdue
+func (t *Task) ShowsOn(date, today time.Time) bool {
+	key := date.Format(DateFormat)
+	if t.IsOverdue(today) {
+		return key == today.Format(DateFormat)
+	}
+	return t.Due == key
+}
+
+// TasksOn returns the tasks listed on date, open ones first, then by due
+// time, priority and title
+func TasksOn(tasks []*Task, date, today time.Time) []*Task {
+	var result []*Task
+	for _, t := range tasks {
+		if t.ShowsOn(date, today) {
+			result = append(result, t)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Done != b.Done {
+			return !a.Done
+		}
+		if a.Due != b.Due {
+			return a.Due < b.Due // overdue ones first
+		}
+		if a.DueTime != b.DueTime {
+			// Tasks due any time go after the ones with a deadline
+			if a.DueTime == "" || b.DueTime == "" {
+				return b.DueTime == ""
+			}
+			return a.DueTime < b.DueTime
+		}
+		if priorityRank(a.Priority) != priorityRank(b.Priority) {
+			return priorityRank(a.Priority) < priorityRank(b.Priority)
+		}
+		return a.Title < b.Title
+	})
+	return result
+}
@@ -0,0 +1,153 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Day text files hold a day's events one per line, in the format of
+// ParseEventLine, each followed by the rest of its event file indented
+// by two spaces:
+//
+//	09:00-10:00 Team Standup [Work]
+//	  id:4f2a9c1e8b7d6a50
+//	  location:Room 4
+//
+//	  Agenda for the quarterly review.
+//	all-day Company Holiday
+//	  id:9b1e0c7d2a3f4e58
+//
+// Events spanning several days or recurring start with their dates,
+// "2025-08-14 " or "2025-08-14..2025-08-17 ". Lines starting with "#"
+// are comments, which aren't kept when the file is written again. Lines
+// without an indented block are events without an ID yet. A title that
+// wouldn't read back the same, because it is empty, has spaces at either
+// end, spans lines or starts with a double quote, is written as a quoted
+// Go string:
+//
+//	09:00 "  Indented title"
+
+// textIndent indents the file content of an event under its line
+const textIndent = "  "
+
+// ParseEventText parses a day text file. With dated, every event line
+// starts with the event's dates.
+func ParseEventText(text string, dated bool) ([]*Event, error) {
+	var events []*Event
+	var block []string
+	line := ""
+	lineNo := 0
+	flush := func() error {
+		if line == "" {
+			return nil
+		}
+		event, err := parseTextEvent(line, strings.Join(block, "\n"), dated)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		events = append(events, event)
+		line, block = "", nil
+		return nil
+	}
+
+	for i, l := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		switch {
+		case strings.TrimSpace(l) == "":
+			if line != "" {
+				block = append(block, "")
+			}
+		case strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t"):
+			if line == "" {
+				return nil, fmt.Errorf("line %d: indented line without an event", i+1)
+			}
+			if rest, ok := strings.CutPrefix(l, textIndent); ok {
+				l = rest
+			} else {
+				l = strings.TrimPrefix(l, "\t")
+			}
+			block = append(block, l)
+		case strings.HasPrefix(l, "#"):
+			// A comment
+		default:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			line, lineNo = l, i+1
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// parseTextEvent parses an event line and its block
+func parseTextEvent(line, block string, dated bool) (*Event, error) {
+	start, end := "", ""
+	if dated {
+		dates, rest, _ := strings.Cut(line, " ")
+		start, end, _ = strings.Cut(dates, "..")
+		if _, err := time.Parse(DateFormat, start); err != nil {
+			return nil, fmt.Errorf("invalid date %q", start)
+		}
+		if _, err := time.Parse(DateFormat, end); end != "" && err != nil {
+			return nil, fmt.Errorf("invalid date %q", end)
+		}
+		line = rest
+	}
+	event, err := ParseEventLine(line)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(event.Title, `"`) {
+		if title, err := strconv.Unquote(event.Title); err == nil {
+			event.Title = title
+		}
+	}
+	categories := event.Categories
+	if err := event.parseFileContent(block); err != nil {
+		return nil, err
+	}
+	event.Categories = categories // the line names them
+	event.StartDate, event.EndDate = start, end
+	return event, nil
+}
+
+// FormatEventText formats events as a day text file. With dated, every
+// event line starts with the event's dates.
+func FormatEventText(events []*Event, dated bool) string {
+	var b strings.Builder
+	for _, e := range events {
+		if dated {
+			b.WriteString(e.StartDate)
+			if e.EndDate != "" {
+				b.WriteString(".." + e.EndDate)
+			}
+			b.WriteString(" ")
+		}
+		line := e
+		if e.Title != strings.TrimSpace(e.Title) || e.Title == "" || strings.ContainsAny(e.Title, "\r\n") || strings.HasPrefix(e.Title, `"`) {
+			line = e.Clone()
+			line.Title = strconv.Quote(e.Title)
+		}
+		b.WriteString(line.FormatEventLine())
+		if len(e.Categories) == 0 && strings.Contains(e.Title, "[") {
+			// Keep ParseEventLine from reading the title's brackets as categories
+			b.WriteString(" []")
+		}
+		b.WriteString("\n")
+
+		rest := e.Clone()
+		rest.Categories = nil
+		content := strings.TrimRight(rest.FormatFileContent(), "\n")
+		for _, l := range strings.Split(content, "\n") {
+			if l != "" {
+				l = textIndent + l
+			}
+			b.WriteString(l + "\n")
+		}
+	}
+	return b.String()
+}
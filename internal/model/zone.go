@@ -0,0 +1,126 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// Events without a TimeZone are floating: their times are read in
+// whatever zone the calendar is displayed in. An event with a TimeZone
+// happens at a fixed instant and is converted for display, so its date
+// and times can differ from the stored ones.
+
+// Zone returns the event's time zone, or nil for floating events
+func (e *Event) Zone() (*time.Location, error) {
+	if e.TimeZone == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", e.TimeZone)
+	}
+	return loc, nil
+}
+
+// InZone returns a copy of the event, stored on date, with its dates and
+// times converted to loc. Floating and all-day events, and events already
+// in loc, are copied unchanged. Converted copies have DisplayZone set and
+// StartDate filled in.
+func (e *Event) InZone(date time.Time, loc *time.Location) *Event {
+	c := e.Clone()
+	src, err := e.Zone()
+	if err != nil || src == nil || e.IsAllDay() || src.String() == loc.String() {
+		return c
+	}
+	if convertZone(c, date, src, loc) != nil {
+		return e.Clone()
+	}
+	c.DisplayZone = loc.String()
+	return c
+}
+
+// FromDisplay undoes InZone: it returns the event as stored in its own
+// time zone and the date it is stored on. Events that weren't converted
+// are returned as they are.
+func (e *Event) FromDisplay(date time.Time) (*Event, time.Time) {
+	if e.DisplayZone == "" {
+		return e, date
+	}
+	c := e.Clone()
+	c.DisplayZone = ""
+	display, err1 := time.LoadLocation(e.DisplayZone)
+	src, err2 := e.Zone()
+	if err1 != nil || err2 != nil || src == nil {
+		return c, date
+	}
+	if convertZone(c, date, display, src) != nil {
+		return c, date
+	}
+
+	stored, _ := time.ParseInLocation(DateFormat, c.StartDate, date.Location())
+	if !c.IsMultiDay() && !c.InSeries() {
+		// Single-day events take their date from the day directory
+		c.StartDate = ""
+		c.EndDate = ""
+	}
+	return c, stored
+}
+
+// convertZone moves e's start and end, read as times on date in from, to
+// the same instants in to. A DST gap in from is skipped forward, the way
+// time.Date normalizes times that don't exist.
+func convertZone(e *Event, date time.Time, from, to *time.Location) error {
+	startDate := e.StartDate
+	if startDate == "" {
+		startDate = date.Format(DateFormat)
+	}
+	start, err := zonedTime(startDate, e.StartTime, from)
+	if err != nil {
+		return err
+	}
+	start = start.In(to)
+	e.StartDate = start.Format(DateFormat)
+	e.StartTime = start.Format("15:04")
+	if e.EndTime == "" {
+		e.EndDate = ""
+		return nil
+	}
+
+	endDate := e.EndDate
+	if endDate == "" {
+		endDate = startDate
+	}
+	end, err := zonedTime(endDate, e.EndTime, from)
+	if err != nil {
+		return err
+	}
+	end = end.In(to)
+	e.EndTime = end.Format("15:04")
+	e.EndDate = ""
+	if key := end.Format(DateFormat); key != e.StartDate {
+		e.EndDate = key
+	}
+	return nil
+}
+
+// zonedTime returns the instant of a "2006-01-02" date and "15:04" time
+// in loc
+func zonedTime(date, clock string, loc *time.Location) (time.Time, error) {
+	d, err := time.Parse(DateFormat, date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	zoned := time.Date(d.Year(), d.Month(), d.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	// In a DST gap time.Date goes back by the length of the gap (02:30
+	// becomes 01:30 EST); go forward by it instead (03:30 EDT)
+	gap := (t.Hour()*60 + t.Minute()) - (zoned.Hour()*60 + zoned.Minute())
+	if gap < 0 {
+		gap += 24 * 60 // a gap at midnight goes back to the day before
+	}
+	zoned = zoned.Add(time.Duration(gap) * time.Minute)
+	return zoned, nil
+}
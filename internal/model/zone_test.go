@@ -0,0 +1,151 @@
+package model
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the same zone data on every machine
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func day(key string) time.Time {
+	d, _ := time.ParseInLocation(DateFormat, key, time.Local)
+	return d
+}
+
+func TestConvertZone(t *testing.T) {
+	tests := []struct {
+		name       string
+		date       string
+		start, end string
+		from, to   string
+		wantDate   string
+		wantStart  string
+		wantEnd    string
+		wantEndDay string
+	}{
+		{
+			name: "summer", date: "2025-08-13", start: "09:00", end: "10:00",
+			from: "America/New_York", to: "Asia/Jerusalem",
+			wantDate: "2025-08-13", wantStart: "16:00", wantEnd: "17:00",
+		},
+		{
+			// The US is on summer time three weeks before the UK
+			name: "DST starts on different dates", date: "2025-03-20", start: "14:00", end: "15:00",
+			from: "Europe/London", to: "America/New_York",
+			wantDate: "2025-03-20", wantStart: "10:00", wantEnd: "11:00",
+		},
+		{
+			// 02:30 doesn't exist in New York that night and is skipped
+			// forward to 03:30 EDT
+			name: "gap", date: "2025-03-09", start: "02:30", end: "04:00",
+			from: "America/New_York", to: "UTC",
+			wantDate: "2025-03-09", wantStart: "07:30", wantEnd: "08:00",
+		},
+		{
+			// Chile moves its clocks from midnight to 01:00
+			name: "gap at midnight", date: "2025-09-07", start: "00:30", end: "02:00",
+			from: "America/Santiago", to: "UTC",
+			wantDate: "2025-09-07", wantStart: "04:30", wantEnd: "05:00",
+		},
+		{
+			// 01:30 happens twice in New York that night; the first, EDT,
+			// is taken
+			name: "overlap", date: "2025-11-02", start: "01:30", end: "02:00",
+			from: "America/New_York", to: "UTC",
+			wantDate: "2025-11-02", wantStart: "05:30", wantEnd: "07:00",
+		},
+		{
+			name: "across midnight", date: "2025-08-12", start: "23:00", end: "23:30",
+			from: "America/New_York", to: "Asia/Jerusalem",
+			wantDate: "2025-08-13", wantStart: "06:00", wantEnd: "06:30",
+		},
+		{
+			name: "ends the next day", date: "2025-08-13", start: "22:00", end: "23:30",
+			from: "UTC", to: "Asia/Jerusalem",
+			wantDate: "2025-08-14", wantStart: "01:00", wantEnd: "02:30",
+		},
+		{
+			name: "start and end on different days", date: "2025-08-13", start: "20:00", end: "22:30",
+			from: "UTC", to: "Asia/Jerusalem",
+			wantDate: "2025-08-13", wantStart: "23:00", wantEnd: "01:30", wantEndDay: "2025-08-14",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Event{StartTime: tt.start, EndTime: tt.end}
+			if err := convertZone(e, day(tt.date), mustLoad(t, tt.from), mustLoad(t, tt.to)); err != nil {
+				t.Fatal(err)
+			}
+			if e.StartDate != tt.wantDate || e.StartTime != tt.wantStart || e.EndTime != tt.wantEnd || e.EndDate != tt.wantEndDay {
+				t.Errorf("got %s %s-%s (ends %q), want %s %s-%s (ends %q)",
+					e.StartDate, e.StartTime, e.EndTime, e.EndDate,
+					tt.wantDate, tt.wantStart, tt.wantEnd, tt.wantEndDay)
+			}
+		})
+	}
+}
+
+func TestInZoneRoundTrip(t *testing.T) {
+	jerusalem := mustLoad(t, "Asia/Jerusalem")
+	tests := []struct {
+		name string
+		date string
+		e    Event
+	}{
+		{"same day", "2025-08-13", Event{StartTime: "08:00", EndTime: "09:00", TimeZone: "America/New_York"}},
+		{"across midnight", "2025-08-12", Event{StartTime: "23:00", EndTime: "23:30", TimeZone: "America/New_York"}},
+		{"overlap", "2025-11-02", Event{StartTime: "01:30", EndTime: "01:45", TimeZone: "America/New_York"}},
+		{"DST ends in Israel", "2025-10-26", Event{StartTime: "01:30", EndTime: "02:30", TimeZone: "Europe/London"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shown := tt.e.InZone(day(tt.date), jerusalem)
+			if shown.DisplayZone != "Asia/Jerusalem" {
+				t.Fatalf("not converted: %+v", shown)
+			}
+			stored, date := shown.FromDisplay(day(tt.date))
+			if key := date.Format(DateFormat); key != tt.date {
+				t.Errorf("stored on %s, want %s", key, tt.date)
+			}
+			if stored.StartTime != tt.e.StartTime || stored.EndTime != tt.e.EndTime || stored.StartDate != "" || stored.EndDate != "" || stored.DisplayZone != "" {
+				t.Errorf("got %+v back, want %+v", stored, tt.e)
+			}
+		})
+	}
+}
+
+func TestFromDisplayMovesDay(t *testing.T) {
+	// Shown at 15:00 in Jerusalem on the 13th, then moved to 06:00, which
+	// is 23:00 the day before in New York
+	e := &Event{StartTime: "08:00", EndTime: "09:00", TimeZone: "America/New_York"}
+	shown := e.InZone(day("2025-08-13"), mustLoad(t, "Asia/Jerusalem"))
+	if shown.StartTime != "15:00" || shown.StartDate != "2025-08-13" {
+		t.Fatalf("shown at %s %s", shown.StartDate, shown.StartTime)
+	}
+	shown.StartTime, shown.EndTime = "06:00", "07:00"
+	stored, date := shown.FromDisplay(day("2025-08-13"))
+	if key := date.Format(DateFormat); key != "2025-08-12" || stored.StartTime != "23:00" || stored.EndTime != "00:00" {
+		t.Errorf("stored at %s %s-%s, want 2025-08-12 23:00-00:00", key, stored.StartTime, stored.EndTime)
+	}
+}
+
+func TestInZoneLeavesFloatingAndAllDay(t *testing.T) {
+	loc := mustLoad(t, "Asia/Tokyo")
+	for _, e := range []*Event{
+		{StartTime: "09:00", EndTime: "10:00"},
+		{StartTime: "all-day", TimeZone: "America/New_York"},
+		{StartTime: "09:00", TimeZone: "Asia/Tokyo"},
+	} {
+		if shown := e.InZone(day("2025-08-13"), loc); shown.DisplayZone != "" || shown.StartTime != e.StartTime {
+			t.Errorf("%+v was converted to %+v", e, shown)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Crash-safety helpers for FileStore.
+//
+// Every event file is written to a temporary file in the same directory
+// and renamed into place, so a reader sees either the old or the new
+// content. Operations that touch more than one file leave a marker
+// behind that recoverDay uses to finish or roll back the operation:
+//
+//	<dir>/.pending-<id>   an UpdateEvent that renames or moves a file; holds the
+//	                      old path relative to the store root. A move to another
+//	                      day leaves it in the old day instead, with the new
+//	                      day's directory on a second line.
+//	<days>/.ready-<date>  a fully written replacement for a day directory
+//	<days>/.replaced-<date> the previous day directory while it is being swapped out
+//
+// Names starting with "." are never read as events, and neither are
+// names starting with "_" (see NoteFilename).
+
+const (
+	tempPrefix     = ".tmp-"
+	pendingPrefix  = ".pending-"
+	stagingPrefix  = ".staging-"
+	readyPrefix    = ".ready-"
+	replacedPrefix = ".replaced-"
+)
+
+// crashPoint, when set, is called at each step of a write, with the files
+// as a crash at that step would leave them. Tests use it to check that
+// recovery always ends up with either the old or the new state.
+var crashPoint func(step string)
+
+// reached calls crashPoint for step
+func reached(step string) {
+	if crashPoint != nil {
+		crashPoint(step)
+	}
+}
+
+// isHiddenName reports whether a directory entry is internal bookkeeping
+func isHiddenName(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// writeFileAtomic writes data to a temporary file next to path, syncs it
+// and renames it over path
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	// Remove the temp file on any failure below
+	ok := false
+	defer func() {
+		if !ok {
+			tmp.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		return err
+	}
+	reached("temp file written")
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	ok = true
+
+	return syncDir(dir)
+}
+
+// WriteFileAtomic writes a file in the data directory the way the stores
+// do, so that a crash leaves either the old or the new file. The caller
+// should hold the lock from LockDir.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeFileAtomic(path, data, perm)
+}
+
+// syncDir flushes a directory so that renames inside it are durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// Some platforms don't support syncing directories; that's not fatal
+	d.Sync()
+	return nil
+}
+
+// recoverDay finishes or rolls back any interrupted operation for a day
+func (s *FileStore) recoverDay(key string) error {
+	daysDir := s.daysDir()
+	dayDir := filepath.Join(daysDir, key)
+	readyDir := filepath.Join(daysDir, readyPrefix+key)
+	replacedDir := filepath.Join(daysDir, replacedPrefix+key)
+
+	// A ready directory is complete, so roll the swap forward
+	if exists(readyDir) {
+		if exists(dayDir) {
+			if exists(replacedDir) {
+				if err := os.RemoveAll(replacedDir); err != nil {
+					return err
+				}
+			}
+			if err := os.Rename(dayDir, replacedDir); err != nil {
+				return err
+			}
+			reached("replaced directory present")
+		}
+		if err := os.Rename(readyDir, dayDir); err != nil {
+			return err
+		}
+		syncDir(daysDir)
+	}
+
+	if exists(replacedDir) {
+		if exists(dayDir) {
+			// Swap finished, drop the old copy
+			if err := os.RemoveAll(replacedDir); err != nil {
+				return err
+			}
+		} else {
+			// Swap never finished, put the old directory back
+			if err := os.Rename(replacedDir, dayDir); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// recoverPending resolves interrupted renames whose new file goes into dir
+func (s *FileStore) recoverPending(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case strings.HasPrefix(name, tempPrefix):
+			// Partially written file that was never renamed into place
+			os.Remove(filepath.Join(dir, name))
+
+		case strings.HasPrefix(name, pendingPrefix):
+			id := strings.TrimPrefix(name, pendingPrefix)
+			markerPath := filepath.Join(dir, name)
+			marker, err := os.ReadFile(markerPath)
+			if err != nil {
+				return err
+			}
+			oldRel, newRel, moved := strings.Cut(strings.TrimSpace(string(marker)), "\n")
+			oldPath := filepath.Join(s.root, oldRel)
+			newDir, others := dir, entries
+			if moved {
+				newDir = filepath.Join(s.root, newRel)
+				if others, err = os.ReadDir(newDir); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+
+			// If the new file made it to disk the old one is stale
+			for _, other := range others {
+				otherName := other.Name()
+				otherPath := filepath.Join(newDir, otherName)
+				if isHiddenName(otherName) || otherPath == oldPath {
+					continue
+				}
+				if event, err := s.readEventFile(otherPath); err == nil && event.ID == id {
+					if err := os.Remove(oldPath); err != nil && !os.IsNotExist(err) {
+						return err
+					}
+					break
+				}
+			}
+			if err := os.Remove(markerPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cleanStaging removes staging directories left by an interrupted
+// SaveDayEvents before it got to mark them ready
+func (s *FileStore) cleanStaging(key string) {
+	matches, _ := filepath.Glob(filepath.Join(s.daysDir(), stagingPrefix+key+"-*"))
+	for _, match := range matches {
+		os.RemoveAll(match)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
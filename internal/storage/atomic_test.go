@@ -0,0 +1,267 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testDate = time.Date(2025, 8, 13, 0, 0, 0, 0, time.Local)
+
+// snapshot is a copy of a data directory taken at a step of a write
+type snapshot struct {
+	step string
+	root string
+}
+
+// crashes runs write and returns a copy of root taken at every step it
+// reached, as a crash at that step would have left it
+func crashes(t *testing.T, root string, write func() error) []snapshot {
+	t.Helper()
+	var snapshots []snapshot
+	crashPoint = func(step string) {
+		dir := t.TempDir()
+		copyTree(t, root, dir)
+		snapshots = append(snapshots, snapshot{step, dir})
+	}
+	defer func() { crashPoint = nil }()
+	if err := write(); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	return snapshots
+}
+
+func copyTree(t *testing.T, from, to string) {
+	t.Helper()
+	err := filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(from, path)
+		target := filepath.Join(to, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		defer dst.Close()
+		_, err = io.Copy(dst, src)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to copy %s: %v", from, err)
+	}
+}
+
+// titles returns the sorted titles of the events stored on date
+func titles(t *testing.T, s *FileStore, date time.Time) string {
+	t.Helper()
+	events, err := s.LoadDayEvents(date)
+	if err != nil {
+		t.Fatalf("LoadDayEvents: %v", err)
+	}
+	var names []string
+	for _, e := range events {
+		names = append(names, e.Title)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// checkRecovered fails if root still holds the bookkeeping of a write.
+// Staging directories are never read and go with the day's next save.
+func checkRecovered(t *testing.T, root string) {
+	t.Helper()
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := info.Name()
+		if info.IsDir() && strings.HasPrefix(name, stagingPrefix) {
+			return filepath.SkipDir
+		}
+		for _, prefix := range []string{tempPrefix, pendingPrefix, readyPrefix, replacedPrefix} {
+			if strings.HasPrefix(name, prefix) {
+				t.Errorf("%s left after recovery", path)
+			}
+		}
+		return nil
+	})
+}
+
+func TestSaveDayEventsCrash(t *testing.T) {
+	root := t.TempDir()
+	s := NewFileStore(root)
+	for _, e := range []*model.Event{
+		{StartTime: "09:00", EndTime: "10:00", Title: "Old One"},
+		{StartTime: "all-day", Title: "Old Two"},
+	} {
+		if err := s.SaveEvent(testDate, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SaveNote(testDate, "Keep me"); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshots := crashes(t, root, func() error {
+		return s.SaveDayEvents(testDate, []*model.Event{
+			{StartTime: "11:00", Title: "New One"},
+			{StartTime: "12:00", EndTime: "13:00", Title: "New Two"},
+		})
+	})
+
+	const old, new = "Old One, Old Two", "New One, New Two"
+	// Once the ready directory exists the save rolls forward
+	want := map[string]string{
+		"temp file written":          old,
+		"ready directory present":    new,
+		"replaced directory present": new,
+	}
+	reached := map[string]bool{}
+	for _, snap := range snapshots {
+		reached[snap.step] = true
+		recovered := NewFileStore(snap.root)
+		if got := titles(t, recovered, testDate); got != want[snap.step] {
+			t.Errorf("crash with %s: got %q, want %q", snap.step, got, want[snap.step])
+		}
+		if note, _ := recovered.LoadNote(testDate); note != "Keep me" {
+			t.Errorf("crash with %s: note is %q", snap.step, note)
+		}
+		checkRecovered(t, snap.root)
+	}
+	for step := range want {
+		if !reached[step] {
+			t.Errorf("SaveDayEvents never reached %s", step)
+		}
+	}
+}
+
+func TestUpdateEventCrash(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(e *model.Event)
+		steps  []string
+	}{
+		{
+			name:   "in place",
+			change: func(e *model.Event) { e.Location = "Room 4" },
+			steps:  []string{"temp file written"},
+		},
+		{
+			name:   "renamed",
+			change: func(e *model.Event) { e.Title = "Renamed Standup" },
+			steps:  []string{"temp file written", "pending marker present", "new file written"},
+		},
+		{
+			name: "moved to spans",
+			change: func(e *model.Event) {
+				e.StartDate, e.EndDate = "2025-08-13", "2025-08-15"
+			},
+			steps: []string{"temp file written", "pending marker present", "new file written"},
+		},
+	}
+	// Once the new file is written the update rolls forward
+	rolledForward := map[string]bool{"new file written": true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			s := NewFileStore(root)
+			event := &model.Event{StartTime: "09:00", EndTime: "10:00", Title: "Standup"}
+			if err := s.SaveEvent(testDate, event); err != nil {
+				t.Fatal(err)
+			}
+			updated := event.Clone()
+			tt.change(updated)
+
+			snapshots := crashes(t, root, func() error {
+				return s.UpdateEvent(testDate, event, updated)
+			})
+
+			reached := map[string]bool{}
+			for _, snap := range snapshots {
+				reached[snap.step] = true
+				events, err := NewFileStore(snap.root).LoadDayEvents(testDate)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(events) != 1 || events[0].ID != event.ID {
+					t.Fatalf("crash with %s: got %d events, want the one", snap.step, len(events))
+				}
+				want := event
+				if rolledForward[snap.step] {
+					want = updated
+				}
+				got := events[0].FormatFileContent() + events[0].Title
+				if got != want.FormatFileContent()+want.Title {
+					t.Errorf("crash with %s: got %+v, want %+v", snap.step, events[0], want)
+				}
+				checkRecovered(t, snap.root)
+			}
+			for _, step := range tt.steps {
+				if !reached[step] {
+					t.Errorf("UpdateEvent never reached %s", step)
+				}
+			}
+		})
+	}
+}
+
+func TestMoveEventCrash(t *testing.T) {
+	nextDay := testDate.AddDate(0, 0, 1)
+	root := t.TempDir()
+	s := NewFileStore(root)
+	event := &model.Event{StartTime: "23:00", EndTime: "23:30", Title: "Call"}
+	if err := s.SaveEvent(testDate, event); err != nil {
+		t.Fatal(err)
+	}
+	moved := event.Clone()
+	moved.StartTime, moved.EndTime = "01:00", "01:30"
+
+	snapshots := crashes(t, root, func() error {
+		return s.MoveEvent(testDate, nextDay, event, moved)
+	})
+
+	reached := map[string]bool{}
+	for _, snap := range snapshots {
+		reached[snap.step] = true
+		// Either day may be read first
+		for _, days := range [][]time.Time{{testDate, nextDay}, {nextDay, testDate}} {
+			root := t.TempDir()
+			copyTree(t, snap.root, root)
+			store := NewFileStore(root)
+			var found []*model.Event
+			for _, day := range days {
+				events, err := store.LoadDayEvents(day)
+				if err != nil {
+					t.Fatal(err)
+				}
+				found = append(found, events...)
+			}
+			if len(found) != 1 || found[0].ID != event.ID {
+				t.Fatalf("crash with %s: got %d events, want the one", snap.step, len(found))
+			}
+			if found[0].StartTime != event.StartTime && (snap.step != "new file written" || found[0].StartTime != moved.StartTime) {
+				t.Errorf("crash with %s: got the event at %s", snap.step, found[0].StartTime)
+			}
+			checkRecovered(t, root)
+		}
+	}
+	for _, step := range []string{"temp file written", "pending marker present", "new file written"} {
+		if !reached[step] {
+			t.Errorf("MoveEvent never reached %s", step)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"sync"
+	"time"
+)
+
+// CachedStore keeps the events of another store in memory, so the views
+// can redraw without reading every day from disk on every frame.
+//
+// A cached day is dropped when the wrapped store reports a new version
+// for it (see DayVersioner), and every write through the cache drops all
+// days, since multi-day and recurring events show up on many of them.
+// Stores that don't report versions are assumed to change only through
+// the cache.
+type CachedStore struct {
+	store Store
+	mu    sync.Mutex
+	days  map[string]*cachedDay
+}
+
+type cachedDay struct {
+	version string
+	events  []*model.Event // nil until loaded
+	note    *string        // nil until loaded
+}
+
+// DayVersioner is implemented by stores whose data can change behind the
+// cache's back, such as files edited by hand or synced from elsewhere.
+type DayVersioner interface {
+	// DayVersion returns a stamp that changes whenever the events or
+	// note of date may have changed
+	DayVersion(date time.Time) string
+}
+
+var (
+	_ Store     = (*CachedStore)(nil)
+	_ NoteStore = (*CachedStore)(nil)
+)
+
+// NewCachedStore wraps store with an in-memory cache
+func NewCachedStore(store Store) *CachedStore {
+	return &CachedStore{store: store, days: make(map[string]*cachedDay)}
+}
+
+// day returns the cache entry for date, emptied if the wrapped store's
+// version of the day has moved on; callers hold c.mu
+func (c *CachedStore) day(date time.Time) *cachedDay {
+	version := ""
+	if v, ok := c.store.(DayVersioner); ok {
+		version = v.DayVersion(date)
+	}
+	key := dayKey(date)
+	d, ok := c.days[key]
+	if !ok || d.version != version {
+		d = &cachedDay{version: version}
+		c.days[key] = d
+	}
+	return d
+}
+
+// LoadDayEvents returns copies of the cached events of date, loading
+// them first if needed
+func (c *CachedStore) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.day(date)
+	if d.events == nil {
+		events, err := c.store.LoadDayEvents(date)
+		if err != nil {
+			return nil, err
+		}
+		if events == nil {
+			events = []*model.Event{}
+		}
+		d.events = events
+	}
+	events := make([]*model.Event, len(d.events))
+	for i, evt := range d.events {
+		events[i] = evt.Clone()
+	}
+	return events, nil
+}
+
+// LoadRange returns the events between from and to, from memory where
+// possible
+func (c *CachedStore) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	return loadRange(from, to, c.LoadDayEvents)
+}
+
+// SaveEvent saves event in the wrapped store
+func (c *CachedStore) SaveEvent(date time.Time, event *model.Event) error {
+	defer c.Invalidate()
+	return c.store.SaveEvent(date, event)
+}
+
+// UpdateEvent updates an event in the wrapped store
+func (c *CachedStore) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	defer c.Invalidate()
+	return c.store.UpdateEvent(date, oldEvent, newEvent)
+}
+
+// MoveEvent moves an event to another day in the wrapped store
+func (c *CachedStore) MoveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error {
+	defer c.Invalidate()
+	return moveEvent(c.store, from, to, oldEvent, newEvent)
+}
+
+// DeleteEvent deletes an event from the wrapped store
+func (c *CachedStore) DeleteEvent(date time.Time, event *model.Event) error {
+	defer c.Invalidate()
+	return c.store.DeleteEvent(date, event)
+}
+
+// DiscardEvent deletes an event from the wrapped store, keeping it out of
+// the trash
+func (c *CachedStore) DiscardEvent(date time.Time, event *model.Event) error {
+	defer c.Invalidate()
+	return discardEvent(c.store, date, event)
+}
+
+// LoadSeries reads a series from the wrapped store; series aren't cached
+func (c *CachedStore) LoadSeries(id string) (*model.Event, []*model.Event, error) {
+	return c.store.LoadSeries(id)
+}
+
+// LoadNote returns the cached note of date, loading it first if needed
+func (c *CachedStore) LoadNote(date time.Time) (string, error) {
+	ns, ok := c.store.(NoteStore)
+	if !ok {
+		return "", nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d := c.day(date)
+	if d.note == nil {
+		note, err := ns.LoadNote(date)
+		if err != nil {
+			return "", err
+		}
+		d.note = &note
+	}
+	return *d.note, nil
+}
+
+// SaveNote saves a note in the wrapped store
+func (c *CachedStore) SaveNote(date time.Time, note string) error {
+	ns, ok := c.store.(NoteStore)
+	if !ok {
+		return fmt.Errorf("this calendar doesn't keep notes")
+	}
+	defer c.Invalidate()
+	return ns.SaveNote(date, note)
+}
+
+// InvalidateDay drops what is cached for the day with key
+// "2006-01-02", after its files changed behind the cache's back
+func (c *CachedStore) InvalidateDay(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.days, key)
+}
+
+// Invalidate drops everything cached
+func (c *CachedStore) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.days = make(map[string]*cachedDay)
+}
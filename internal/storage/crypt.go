@@ -0,0 +1,401 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"bubblecal/internal/model"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// An encrypted calendar directory holds encryption.json, which has the
+// salt the key is derived from with PBKDF2, and every event, note, task
+// and trash file is sealed with AES-GCM under that key:
+//
+//	<magic><12-byte nonce><ciphertext and tag>
+//
+// Event files are sealed with the name the event would have unencrypted
+// as their first line, so that with obscure_names the title in the name
+// on disk can be replaced by the event's ID:
+//
+//	<root>/days/2025-08-13/0900-1000-4f2a9c1e8b7d6a50
+//
+// Files without the magic prefix are read as they are, so events written
+// by hand or by an older bubblecal still load; they are sealed the next
+// time they are written.
+
+// encryptionName is the file in a data directory holding cipherParams
+const encryptionName = "encryption.json"
+
+// sealedMagic starts every sealed file
+var sealedMagic = []byte("\x00bubblecal-aes-gcm\n")
+
+// checkText is sealed into cipherParams to tell whether a passphrase is
+// right
+const checkText = "bubblecal"
+
+// keyIterations is how many PBKDF2 rounds a new key takes
+const keyIterations = 600000
+
+// ErrPassphrase is returned for a passphrase that doesn't open a calendar
+var ErrPassphrase = errors.New("wrong passphrase")
+
+// ErrEncrypted is returned for a sealed file read without the passphrase
+var ErrEncrypted = errors.New("file is encrypted")
+
+// cipherParams is the content of encryption.json
+type cipherParams struct {
+	Salt         []byte `json:"salt"`
+	Iterations   int    `json:"iterations"`
+	ObscureNames bool   `json:"obscure_names"`
+	Check        []byte `json:"check"`
+}
+
+// fileCipher seals and opens the files of an encrypted data directory
+type fileCipher struct {
+	aead         cipher.AEAD
+	obscureNames bool
+}
+
+// IsEncrypted reports whether the data directory root is encrypted
+func IsEncrypted(root string) bool {
+	_, err := os.Stat(filepath.Join(root, encryptionName))
+	return err == nil
+}
+
+// NewEncryptedFileStore opens the encrypted data directory root with
+// passphrase, returning ErrPassphrase if it's the wrong one
+func NewEncryptedFileStore(root, passphrase string) (*FileStore, error) {
+	c, err := openCipher(root, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	s := NewFileStore(root)
+	s.cipher = c
+	return s, nil
+}
+
+// Encrypted reports whether the store seals what it writes
+func (s *FileStore) Encrypted() bool {
+	return s.cipher != nil
+}
+
+// newCipher derives the key for params from passphrase
+func newCipher(params *cipherParams, passphrase string) (*fileCipher, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, params.Salt, params.Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fileCipher{aead: aead, obscureNames: params.ObscureNames}, nil
+}
+
+// openCipher reads the encryption.json of root and checks passphrase
+// against it
+func openCipher(root, passphrase string) (*fileCipher, error) {
+	data, err := os.ReadFile(filepath.Join(root, encryptionName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", encryptionName, err)
+	}
+	var params cipherParams
+	if err := json.Unmarshal(data, &params); err != nil || len(params.Salt) == 0 || params.Iterations <= 0 {
+		return nil, fmt.Errorf("invalid %s", encryptionName)
+	}
+	c, err := newCipher(&params, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if check, sealed, err := c.open(params.Check); err != nil || !sealed || string(check) != checkText {
+		return nil, ErrPassphrase
+	}
+	return c, nil
+}
+
+// seal encrypts data
+func (c *fileCipher) seal(data []byte) []byte {
+	nonce := make([]byte, c.aead.NonceSize())
+	rand.Read(nonce)
+	out := append(append([]byte(nil), sealedMagic...), nonce...)
+	return c.aead.Seal(out, nonce, data, nil)
+}
+
+// open decrypts data if it was sealed and returns it as it is otherwise
+func (c *fileCipher) open(data []byte) ([]byte, bool, error) {
+	if !bytes.HasPrefix(data, sealedMagic) {
+		return data, false, nil
+	}
+	if c == nil {
+		return nil, true, ErrEncrypted
+	}
+	data = data[len(sealedMagic):]
+	if len(data) < c.aead.NonceSize() {
+		return nil, true, fmt.Errorf("encrypted file is truncated")
+	}
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, true, fmt.Errorf("encrypted file is damaged or from another calendar")
+	}
+	return plain, true, nil
+}
+
+// readFile reads the file at path, opening it if it's sealed
+func (s *FileStore) readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err = s.cipher.open(data)
+	return data, err
+}
+
+// writeFile atomically writes data to path, sealed if the store is
+// encrypted
+func (s *FileStore) writeFile(path string, data []byte) error {
+	if s.cipher != nil {
+		return writeFileAtomic(path, s.cipher.seal(data), 0600)
+	}
+	return writeFileAtomic(path, data, 0644)
+}
+
+// eventFilename returns the name event is stored under: its
+// storedFilename, with the title replaced by the ID when names are
+// obscured
+func (s *FileStore) eventFilename(event *model.Event) string {
+	if s.cipher == nil || !s.cipher.obscureNames {
+		return storedFilename(event)
+	}
+	hidden := event.Clone()
+	hidden.Title = event.ID
+	return storedFilename(hidden)
+}
+
+// taskFilename returns the name task is stored under, leaving out the
+// title when names are obscured
+func (s *FileStore) taskFilename(task *model.Task) string {
+	if s.cipher == nil || !s.cipher.obscureNames {
+		return task.Filename()
+	}
+	return task.ID + "-"
+}
+
+// writeEvent writes event to the file at path
+func (s *FileStore) writeEvent(path string, event *model.Event) error {
+	content := event.FormatFileContent()
+	if s.cipher != nil {
+		content = storedFilename(event) + "\n" + content
+	}
+	return s.writeFile(path, []byte(content))
+}
+
+// decodeEvent parses data, read from the event file at rel (relative to
+// the store root), as the event it holds
+func (s *FileStore) decodeEvent(rel string, data []byte) (*model.Event, error) {
+	data, sealed, err := s.cipher.open(data)
+	if err != nil {
+		return nil, err
+	}
+	if sealed {
+		name, content, _ := strings.Cut(string(data), "\n")
+		return parseStoredEvent(filepath.Join(filepath.Dir(rel), name), content)
+	}
+	return parseStoredEvent(rel, string(data))
+}
+
+// Encrypt encrypts the unencrypted data directory root with passphrase,
+// replacing titles in filenames by IDs if obscureNames is set. It returns
+// how many files it sealed.
+func Encrypt(root, passphrase string, obscureNames bool) (int, error) {
+	if IsEncrypted(root) {
+		return 0, fmt.Errorf("the calendar is already encrypted")
+	}
+	if passphrase == "" {
+		return 0, fmt.Errorf("the passphrase is empty")
+	}
+	unlock, err := LockDir(root)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	params := &cipherParams{Salt: make([]byte, 16), Iterations: keyIterations, ObscureNames: obscureNames}
+	rand.Read(params.Salt)
+	c, err := newCipher(params, passphrase)
+	if err != nil {
+		return 0, err
+	}
+	params.Check = c.seal([]byte(checkText))
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	// Unsealed files still load, so the directory can be used from here
+	// on even if encrypting the files is interrupted
+	if err := writeFileAtomic(filepath.Join(root, encryptionName), data, 0644); err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", encryptionName, err)
+	}
+
+	from := NewFileStore(root)
+	to := NewFileStore(root)
+	to.cipher = c
+	n, err := recode(from, to)
+	if err != nil {
+		return n, err
+	}
+	// The undo log holds whole events; an encrypted calendar keeps it in
+	// memory only
+	if err := os.Remove(filepath.Join(root, "undo.json")); err != nil && !os.IsNotExist(err) {
+		return n, err
+	}
+	return n, nil
+}
+
+// Decrypt turns the encrypted data directory root back into plain files
+// and returns how many files it opened
+func Decrypt(root, passphrase string) (int, error) {
+	if !IsEncrypted(root) {
+		return 0, fmt.Errorf("the calendar isn't encrypted")
+	}
+	from, err := NewEncryptedFileStore(root, passphrase)
+	if err != nil {
+		return 0, err
+	}
+	unlock, err := LockDir(root)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	n, err := recode(from, NewFileStore(root))
+	if err != nil {
+		return n, err
+	}
+	// Only once every file is plain again; until then a second run can
+	// finish the job
+	if err := os.Remove(filepath.Join(root, encryptionName)); err != nil {
+		return n, fmt.Errorf("failed to remove %s: %w", encryptionName, err)
+	}
+	return n, nil
+}
+
+// recode rewrites every event, note, task and trash file of from the way
+// to writes them. Both stores share a root, whose lock the caller holds.
+func recode(from, to *FileStore) (int, error) {
+	n := 0
+	rename := to.Encrypted() && to.cipher.obscureNames || from.Encrypted() && from.cipher.obscureNames
+
+	var eventDirs []string
+	days, err := os.ReadDir(from.daysDir())
+	if err != nil && !os.IsNotExist(err) {
+		return n, fmt.Errorf("failed to read days directory: %w", err)
+	}
+	for _, day := range days {
+		if day.IsDir() && !isHiddenName(day.Name()) {
+			eventDirs = append(eventDirs, filepath.Join(from.daysDir(), day.Name()))
+		}
+	}
+	eventDirs = append(eventDirs, from.spansDir(), from.recurringDir())
+
+	for _, dir := range eventDirs {
+		entries, err := from.readEventDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			event, err := from.readEventFile(path)
+			if err != nil {
+				return n, fmt.Errorf("%w; run \"bubblecal doctor\" first", err)
+			}
+			if event.ID == "" {
+				event.ID = model.NewID()
+			}
+			if !rename || to.eventFilename(event) == entry.Name() {
+				err = to.writeEvent(path, event)
+			} else if _, err = to.writeEventFile(dir, event); err == nil {
+				err = os.Remove(path)
+			}
+			if err != nil {
+				return n, fmt.Errorf("failed to rewrite %s: %w", entry.Name(), err)
+			}
+			n++
+		}
+		if note := filepath.Join(dir, NoteFilename); fileExists(note) {
+			if err := recodeFile(from, to, note, note); err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+
+	tasks, err := from.LoadTasks()
+	if err != nil {
+		return n, err
+	}
+	for _, task := range tasks {
+		old, err := from.taskFile(task.ID)
+		if err != nil || old == "" {
+			return n, fmt.Errorf("failed to find task %s", task.Title)
+		}
+		if err := recodeFile(from, to, old, filepath.Join(to.tasksDir(), to.taskFilename(task))); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	trash, err := os.ReadDir(from.trashDir())
+	if err != nil && !os.IsNotExist(err) {
+		return n, fmt.Errorf("failed to read trash: %w", err)
+	}
+	for _, entry := range trash {
+		if entry.IsDir() || isHiddenName(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(from.trashDir(), entry.Name())
+		if err := recodeFile(from, to, path, path); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// recodeFile reads the file at path with from and writes it to newPath
+// with to, removing path if they differ
+func recodeFile(from, to *FileStore, path, newPath string) error {
+	data, err := from.readFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	if err := to.writeFile(newPath, data); err != nil {
+		return fmt.Errorf("failed to rewrite %s: %w", filepath.Base(path), err)
+	}
+	if newPath != path {
+		return os.Remove(path)
+	}
+	return nil
+}
+
+// fileExists reports whether there is a file at path
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
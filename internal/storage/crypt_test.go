@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// seedCalendar writes one of everything a calendar directory holds: a
+// plain event, a span, a recurring series with an override, a note, a
+// task, and a deleted event and task in the trash
+func seedCalendar(t *testing.T, root string) {
+	t.Helper()
+	s := NewFileStore(root)
+	day := testDate
+	for _, e := range []*model.Event{
+		{StartTime: "09:00", EndTime: "10:00", Title: "Secret Standup", Location: "Room 4"},
+		{StartTime: "all-day", Title: "Secret Trip", StartDate: "2025-08-13", EndDate: "2025-08-15"},
+		{StartTime: "11:00", EndTime: "12:00", Title: "Secret Weekly", Recurrence: &model.Recurrence{Freq: model.FreqWeekly, Interval: 1}},
+		{StartTime: "14:00", Title: "Secret Gone"},
+	} {
+		if err := s.SaveEvent(day, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	events, err := s.LoadDayEvents(day.AddDate(0, 0, 7))
+	if err != nil || len(events) != 1 {
+		t.Fatalf("got %v, %v a week later, want the series", events, err)
+	}
+	moved := events[0].Clone()
+	moved.StartTime, moved.EndTime = "13:00", "14:00"
+	if err := UpdateOccurrence(s, day.AddDate(0, 0, 7), events[0], moved, ScopeThis); err != nil {
+		t.Fatal(err)
+	}
+	events, _ = s.LoadDayEvents(day)
+	for _, e := range events {
+		if e.Title == "Secret Gone" {
+			if err := s.DeleteEvent(day, e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := s.SaveNote(day, "Secret note"); err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range []*model.Task{
+		{Title: "Secret Report", Due: "2025-08-20"},
+		{Title: "Secret Errand", Due: "2025-08-21"},
+	} {
+		if err := s.SaveTask(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tasks, _ := s.LoadTasks()
+	for _, task := range tasks {
+		if task.Title == "Secret Errand" {
+			if err := s.DeleteTask(task); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// readTree returns the files under root, keyed by their path relative to
+// it, leaving out the lock file
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || info.Name() == lockName {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// sameTree reports the differences between two readTree results
+func sameTree(t *testing.T, got, want map[string]string) {
+	t.Helper()
+	for rel, content := range want {
+		if got[rel] != content {
+			t.Errorf("%s: got %q, want %q", rel, got[rel], content)
+		}
+	}
+	for rel := range got {
+		if _, ok := want[rel]; !ok {
+			t.Errorf("%s wasn't there before", rel)
+		}
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	seedCalendar(t, root)
+	plain := readTree(t, root)
+	before := NewFileStore(root)
+	wantDay, _ := before.LoadDayEvents(testDate)
+	wantWeek, _ := before.LoadDayEvents(testDate.AddDate(0, 0, 7))
+	wantTasks, _ := before.LoadTasks()
+	wantTrash, _ := before.LoadTrash()
+	if len(wantDay) != 3 || len(wantWeek) != 1 || len(wantTasks) != 1 || len(wantTrash) != 2 {
+		t.Fatalf("seeded %d, %d events, %d tasks and %d in the trash", len(wantDay), len(wantWeek), len(wantTasks), len(wantTrash))
+	}
+
+	if _, err := Encrypt(root, "correct horse", true); err != nil {
+		t.Fatal(err)
+	}
+	for rel, content := range readTree(t, root) {
+		if strings.Contains(rel, "Secret") || strings.Contains(content, "Secret") {
+			t.Errorf("%s gives away a title", rel)
+		}
+	}
+
+	// Everything reads back through the passphrase
+	s, err := NewEncryptedFileStore(root, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameEvents := func(date string, got, want []*model.Event) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s: got %d events, want %d", date, len(got), len(want))
+		}
+		for i := range want {
+			if got[i].ID != want[i].ID || got[i].FormatFileContent() != want[i].FormatFileContent() || got[i].Title != want[i].Title {
+				t.Errorf("%s: got %+v, want %+v", date, got[i], want[i])
+			}
+		}
+	}
+	day, _ := s.LoadDayEvents(testDate)
+	sameEvents("2025-08-13", day, wantDay)
+	week, _ := s.LoadDayEvents(testDate.AddDate(0, 0, 7))
+	sameEvents("2025-08-20", week, wantWeek)
+	if note, _ := s.LoadNote(testDate); note != "Secret note" {
+		t.Errorf("note is %q", note)
+	}
+	if tasks, _ := s.LoadTasks(); len(tasks) != 1 || tasks[0].FormatFileContent() != wantTasks[0].FormatFileContent() {
+		t.Errorf("got tasks %v, want %v", tasks, wantTasks)
+	}
+	trash, _ := s.LoadTrash()
+	if len(trash) != len(wantTrash) {
+		t.Fatalf("got %d in the trash, want %d", len(trash), len(wantTrash))
+	}
+	for i := range trash {
+		if trash[i].Title() != wantTrash[i].Title() {
+			t.Errorf("trash %d is %q, want %q", i, trash[i].Title(), wantTrash[i].Title())
+		}
+	}
+
+	// Decrypting gives back the files as they were
+	if _, err := Decrypt(root, "correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	sameTree(t, readTree(t, root), plain)
+}
+
+func TestWrongPassphraseChangesNothing(t *testing.T) {
+	root := t.TempDir()
+	seedCalendar(t, root)
+	if _, err := Encrypt(root, "correct horse", true); err != nil {
+		t.Fatal(err)
+	}
+	sealed := readTree(t, root)
+
+	if _, err := NewEncryptedFileStore(root, "wrong horse"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("opening with the wrong passphrase: %v", err)
+	}
+	if _, err := Decrypt(root, "wrong horse"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("decrypting with the wrong passphrase: %v", err)
+	}
+	sameTree(t, readTree(t, root), sealed)
+}
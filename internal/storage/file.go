@@ -0,0 +1,655 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"bubblecal/internal/model"
+	"sync"
+	"time"
+)
+
+// FileStore keeps each event in its own file. Single-day events live in
+// a per-day directory, multi-day events are stored once under spans/ and
+// recurring series once under recurring/:
+//
+//	<root>/days/2025-08-13/0900-1000-Team_Standup
+//	<root>/spans/2025-08-14_2025-08-17-allday-Vacation
+//	<root>/recurring/2025-08-11-0930-0945-Daily_Standup
+type FileStore struct {
+	root string
+	mu   *sync.Mutex // held along with the directory lock
+	seen *versions   // the directories as the events were last loaded
+
+	cipher *fileCipher // nil unless the directory is encrypted
+}
+
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore creates a FileStore rooted at root (usually GetCalendarDir())
+func NewFileStore(root string) *FileStore {
+	return &FileStore{root: root, mu: &sync.Mutex{}, seen: newVersions()}
+}
+
+// Root returns the directory holding the store's data
+func (s *FileStore) Root() string {
+	return s.root
+}
+
+// DayDirPath returns the directory path for a specific date
+func (s *FileStore) DayDirPath(date time.Time) string {
+	return filepath.Join(s.daysDir(), dayKey(date))
+}
+
+func (s *FileStore) daysDir() string {
+	return filepath.Join(s.root, "days")
+}
+
+func (s *FileStore) spansDir() string {
+	return filepath.Join(s.root, "spans")
+}
+
+func (s *FileStore) recurringDir() string {
+	return filepath.Join(s.root, "recurring")
+}
+
+var _ DayVersioner = (*FileStore)(nil)
+
+// DayVersion stamps the modification times of the directories date's
+// events and note are read from. FileStore writes by renaming temporary
+// files into place, which moves a directory's time on; a file edited in
+// place by another program is only picked up once its directory changes.
+func (s *FileStore) DayVersion(date time.Time) string {
+	version := ""
+	for _, dir := range []string{s.DayDirPath(date), s.spansDir(), s.recurringDir()} {
+		if info, err := os.Stat(dir); err == nil {
+			version += fmt.Sprintf("%d/", info.ModTime().UnixNano())
+		} else {
+			version += "-/"
+		}
+	}
+	return version
+}
+
+// eventDirs returns the directories holding the events shown on date
+func (s *FileStore) eventDirs(date time.Time) []string {
+	return []string{s.DayDirPath(date), s.spansDir(), s.recurringDir()}
+}
+
+// lock takes the directory lock for a write to the events or note of each
+// date. It refuses with ErrConflict if another program changed them since
+// they were loaded. The returned function records the store's own changes
+// and releases the lock.
+func (s *FileStore) lock(dates ...time.Time) (func(), error) {
+	s.mu.Lock()
+	release, err := LockDir(s.root)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	var dirs []string
+	for _, date := range dates {
+		dirs = append(dirs, s.eventDirs(date)...)
+	}
+	if dir := s.seen.changed(dirs...); dir != "" {
+		release()
+		s.mu.Unlock()
+		what := "multi-day or recurring events"
+		if filepath.Dir(dir) == s.daysDir() {
+			what = "the events of " + filepath.Base(dir)
+		}
+		return nil, fmt.Errorf("another program changed %s since they were loaded; %w", what, ErrConflict)
+	}
+	return func() {
+		s.seen.record(dirs...)
+		release()
+		s.mu.Unlock()
+	}, nil
+}
+
+// readLock takes the directory lock for a read, which may finish an
+// interrupted write, give old files IDs or move overnight events. A data
+// directory that doesn't exist yet, or whose lock file can't be opened
+// because it is read-only, is read without it: nothing can be changed
+// there anyway.
+func (s *FileStore) readLock() func() {
+	s.mu.Lock()
+	if !exists(s.root) {
+		return s.mu.Unlock
+	}
+	release, err := LockDir(s.root)
+	if err != nil {
+		return s.mu.Unlock
+	}
+	return func() {
+		release()
+		s.mu.Unlock()
+	}
+}
+
+// dirFor returns the directory an event is stored in
+func (s *FileStore) dirFor(date time.Time, event *model.Event) string {
+	if event.InSeries() {
+		return s.recurringDir()
+	}
+	if event.IsMultiDay() {
+		return s.spansDir()
+	}
+	return s.DayDirPath(date)
+}
+
+// LoadDayEvents loads the events of a day directory plus any multi-day
+// events and occurrences of recurring series that cover the date
+func (s *FileStore) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	unlock := s.readLock()
+	defer unlock()
+	var events []*model.Event
+	err := s.seen.read(s.eventDirs(date), func() error {
+		var err error
+		events, err = s.loadDay(date)
+		return err
+	})
+	return events, err
+}
+
+// loadDay reads the events shown on date; the caller holds the read lock
+func (s *FileStore) loadDay(date time.Time) ([]*model.Event, error) {
+	events, err := s.loadDir(s.DayDirPath(date), func() error {
+		// An interrupted update moving an event out of the day leaves its
+		// marker in spans/ or recurring/, which are read after the day
+		for _, dir := range []string{s.spansDir(), s.recurringDir()} {
+			if err := s.recoverPending(dir); err != nil {
+				return err
+			}
+		}
+		return s.recoverDay(dayKey(date))
+	})
+	if err != nil {
+		return nil, err
+	}
+	events = s.migrateOvernight(date, events)
+
+	spans, err := s.loadDir(s.spansDir(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, span := range spans {
+		if span.Covers(date) {
+			events = append(events, span)
+		}
+	}
+
+	series, err := s.loadDir(s.recurringDir(), nil)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, expandSeries(series, date)...)
+
+	// Sort events
+	sortEvents(events, date)
+
+	return events, nil
+}
+
+// loadDir reads every event file in dirPath, running recover first
+func (s *FileStore) loadDir(dirPath string, recover func() error) ([]*model.Event, error) {
+	// Finish any write that was interrupted for this directory
+	if recover != nil {
+		if err := recover(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to recover %s: %v\n", filepath.Base(dirPath), err)
+		}
+	}
+
+	// If directory doesn't exist, return empty list (no events)
+	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
+		return []*model.Event{}, nil
+	}
+
+	entries, err := s.readEventDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*model.Event
+	for _, entry := range entries {
+		filename := entry.Name()
+		filePath := filepath.Join(dirPath, filename)
+
+		event, err := s.readEventFile(filePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+
+		// Files written before events had IDs get one on first load
+		if event.ID == "" {
+			event.ID = model.NewID()
+			if err := s.writeEvent(filePath, event); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to assign id to %s: %v\n", filename, err)
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// migrateOvernight moves events that were saved in a day directory with
+// an end time before their start time to spans/, so that the next day
+// shows them too. It returns the events that stay in the day directory.
+func (s *FileStore) migrateOvernight(date time.Time, events []*model.Event) []*model.Event {
+	kept := events[:0]
+	for _, event := range events {
+		if !event.IsOvernight() {
+			kept = append(kept, event)
+			continue
+		}
+		spanned := event.Clone()
+		spanned.SpanOvernight(date)
+		if err := s.updateEvent(date, event, spanned); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to move overnight event %s: %v\n", event.Title, err)
+			kept = append(kept, event)
+		}
+	}
+	return kept
+}
+
+// LoadRange loads the events of every day between from and to
+func (s *FileStore) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	return loadRange(from, to, s.LoadDayEvents)
+}
+
+// SaveDayEvents replaces all single-day events of a day. The new events
+// are written to a staging directory which is then swapped with the day
+// directory, so an interrupted save leaves either the old or the new set
+// of events.
+func (s *FileStore) SaveDayEvents(date time.Time, events []*model.Event) error {
+	unlock, err := s.lock(date)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	key := dayKey(date)
+	daysDir := s.daysDir()
+	if err := os.MkdirAll(daysDir, 0755); err != nil {
+		return fmt.Errorf("failed to create days directory: %w", err)
+	}
+	if err := s.recoverDay(key); err != nil {
+		return fmt.Errorf("failed to recover previous save: %w", err)
+	}
+	s.cleanStaging(key)
+
+	// Write every event into a fresh staging directory
+	stagingDir, err := os.MkdirTemp(daysDir, stagingPrefix+key+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	for _, event := range events {
+		if event.IsMultiDay() || event.IsRecurring() {
+			os.RemoveAll(stagingDir)
+			return fmt.Errorf("event %q isn't a single-day event and can't be saved as part of a day", event.Title)
+		}
+		if _, err := s.writeEventFile(stagingDir, event); err != nil {
+			os.RemoveAll(stagingDir)
+			return fmt.Errorf("failed to save event: %w", err)
+		}
+	}
+	// The day's note moves along with its events
+	if note, err := os.ReadFile(s.notePath(date)); err == nil {
+		if err := writeFileAtomic(filepath.Join(stagingDir, NoteFilename), note, 0644); err != nil {
+			os.RemoveAll(stagingDir)
+			return fmt.Errorf("failed to keep note: %w", err)
+		}
+	}
+	syncDir(stagingDir)
+
+	// Mark the staging directory complete; from here on recovery rolls forward
+	readyDir := filepath.Join(daysDir, readyPrefix+key)
+	if err := os.Rename(stagingDir, readyDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("failed to stage events: %w", err)
+	}
+	syncDir(daysDir)
+	reached("ready directory present")
+
+	if err := s.recoverDay(key); err != nil {
+		return fmt.Errorf("failed to swap in new events: %w", err)
+	}
+
+	// Don't leave an empty directory behind for a day without events; a
+	// directory still holding a note isn't removed
+	if len(events) == 0 {
+		os.Remove(s.DayDirPath(date))
+	}
+
+	return nil
+}
+
+// SaveEvent saves a single event to its own file. Events without an ID
+// are assigned a new one. Multi-day events are stored under spans/ and
+// recurring series under recurring/, starting on date unless StartDate
+// says otherwise.
+func (s *FileStore) SaveEvent(date time.Time, event *model.Event) error {
+	unlock, err := s.lock(date)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	prepareEvent(date, event)
+
+	// An event saved again with its ID, say by undoing its deletion,
+	// leaves the trash
+	if event.ID != "" {
+		s.dropTrashed(event.ID)
+	}
+
+	// Ensure directories exist
+	dirPath := s.dirFor(date, event)
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return fmt.Errorf("failed to create event directory: %w", err)
+	}
+
+	if _, err := s.writeEventFile(dirPath, event); err != nil {
+		return fmt.Errorf("failed to write event file: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEvent moves the file of the event with the same ID to the trash
+func (s *FileStore) DeleteEvent(date time.Time, eventToDelete *model.Event) error {
+	return s.deleteEvent(date, eventToDelete, true)
+}
+
+// DiscardEvent removes the file of the event with the same ID
+func (s *FileStore) DiscardEvent(date time.Time, event *model.Event) error {
+	return s.deleteEvent(date, event, false)
+}
+
+// deleteEvent removes the file of the event with the same ID, moving it
+// to the trash if trash is set
+func (s *FileStore) deleteEvent(date time.Time, eventToDelete *model.Event, trash bool) error {
+	unlock, err := s.lock(date)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	filePath, err := s.findEventFile(date, eventToDelete.ID)
+	if err != nil {
+		return err
+	}
+
+	if trash {
+		err = s.moveToTrash(date, filePath, eventToDelete.ID)
+	} else {
+		err = os.Remove(filePath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete event file: %w", err)
+	}
+
+	// Clean up empty day directory
+	dirPath := s.DayDirPath(date)
+	if entries, _ := os.ReadDir(dirPath); len(entries) == 0 {
+		os.Remove(dirPath)
+	}
+
+	return nil
+}
+
+// UpdateEvent replaces the event with oldEvent's ID. The file is renamed
+// (or moved between days/ and spans/) when needed; the ID is carried
+// over to newEvent.
+func (s *FileStore) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	unlock, err := s.lock(date)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.updateEvent(date, oldEvent, newEvent)
+}
+
+// MoveEvent replaces the event with oldEvent's ID on from by newEvent on
+// to, in one step that recovers like an update if it is interrupted
+func (s *FileStore) MoveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error {
+	unlock, err := s.lock(from, to)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.moveEvent(from, to, oldEvent, newEvent)
+}
+
+// updateEvent does UpdateEvent's work; the caller holds the lock
+func (s *FileStore) updateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	return s.moveEvent(date, date, oldEvent, newEvent)
+}
+
+// moveEvent does MoveEvent's work; the caller holds the lock
+func (s *FileStore) moveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error {
+	oldPath, err := s.findEventFile(from, oldEvent.ID)
+	if err != nil {
+		return err
+	}
+	newEvent.ID = oldEvent.ID
+	prepareEvent(to, newEvent)
+	if stored, err := s.readEventFile(oldPath); err == nil {
+		rebaseSeries(stored, oldEvent, newEvent)
+		keepExtra(stored, newEvent)
+	}
+
+	dirPath := s.dirFor(to, newEvent)
+	newPath := filepath.Join(dirPath, s.eventFilename(newEvent))
+	if newPath == oldPath {
+		if err := s.writeEvent(oldPath, newEvent); err != nil {
+			return fmt.Errorf("failed to write event file: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return fmt.Errorf("failed to create event directory: %w", err)
+	}
+
+	// Renaming takes two steps, so record the old path first. If we
+	// crash in between, recovery removes whichever copy is stale.
+	oldRel, err := filepath.Rel(s.root, oldPath)
+	if err != nil {
+		return fmt.Errorf("failed to prepare update: %w", err)
+	}
+	markerPath, marker := filepath.Join(dirPath, pendingPrefix+newEvent.ID), oldRel
+	if oldDir := filepath.Dir(oldPath); oldDir == s.DayDirPath(from) && dirPath == s.DayDirPath(to) && oldDir != dirPath {
+		// Moving to another day: the old day is read without the new
+		// one, so it keeps the marker and says where the new file goes
+		newRel, err := filepath.Rel(s.root, dirPath)
+		if err != nil {
+			return fmt.Errorf("failed to prepare update: %w", err)
+		}
+		markerPath, marker = filepath.Join(oldDir, pendingPrefix+newEvent.ID), oldRel+"\n"+newRel
+	}
+	if err := writeFileAtomic(markerPath, []byte(marker), 0644); err != nil {
+		return fmt.Errorf("failed to prepare update: %w", err)
+	}
+	reached("pending marker present")
+
+	// Save the new file before removing the old one
+	if _, err := s.writeEventFile(dirPath, newEvent); err != nil {
+		os.Remove(markerPath)
+		return fmt.Errorf("failed to save updated event: %w", err)
+	}
+	reached("new file written")
+	if err := os.Remove(oldPath); err != nil {
+		return fmt.Errorf("failed to delete old event: %w", err)
+	}
+	os.Remove(markerPath)
+	syncDir(dirPath)
+
+	// Moving an event off a day, or a span back to a single day, may
+	// leave its old day empty
+	if oldDir := s.DayDirPath(from); filepath.Dir(oldPath) == oldDir {
+		if entries, _ := os.ReadDir(oldDir); len(entries) == 0 {
+			os.Remove(oldDir)
+		}
+	}
+
+	return nil
+}
+
+// LoadSeries returns the recurring series with the given ID and its
+// overrides
+func (s *FileStore) LoadSeries(id string) (*model.Event, []*model.Event, error) {
+	unlock := s.readLock()
+	defer unlock()
+	var events []*model.Event
+	err := s.seen.read([]string{s.recurringDir()}, func() error {
+		var err error
+		events, err = s.loadDir(s.recurringDir(), nil)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return findSeries(events, id)
+}
+
+// findEventFile returns the path of the event file with the given ID,
+// looking in the day directory first and then in spans/ and recurring/
+func (s *FileStore) findEventFile(date time.Time, id string) (string, error) {
+	if id == "" {
+		return "", fmt.Errorf("event has no id")
+	}
+
+	for _, dirPath := range []string{s.DayDirPath(date), s.spansDir(), s.recurringDir()} {
+		entries, err := s.readEventDir(dirPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return "", err
+		}
+
+		for _, entry := range entries {
+			filePath := filepath.Join(dirPath, entry.Name())
+			event, err := s.readEventFile(filePath)
+			if err != nil {
+				continue
+			}
+			if event.ID == id {
+				return filePath, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("event not found")
+}
+
+// readEventDir lists the event files of a directory, resolving any
+// interrupted update it finds along the way
+func (s *FileStore) readEventDir(dirPath string) ([]os.DirEntry, error) {
+	for attempt := 0; ; attempt++ {
+		entries, err := os.ReadDir(dirPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event directory: %w", err)
+		}
+
+		var files []os.DirEntry
+		hasHidden := false
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if isHiddenName(entry.Name()) {
+				hasHidden = true
+				continue
+			}
+			if isReservedName(entry.Name()) {
+				continue
+			}
+			files = append(files, entry)
+		}
+
+		if !hasHidden || attempt > 0 {
+			return files, nil
+		}
+
+		// Recovery may remove stale files, so list again afterwards
+		if err := s.recoverPending(dirPath); err != nil {
+			return nil, fmt.Errorf("failed to recover event directory: %w", err)
+		}
+	}
+}
+
+// writeEventFile atomically writes event into dirPath, picking a free
+// filename, and returns the path it used. Events without an ID get one.
+func (s *FileStore) writeEventFile(dirPath string, event *model.Event) (string, error) {
+	if event.ID == "" {
+		event.ID = model.NewID()
+	}
+
+	filename := s.eventFilename(event)
+	filePath := filepath.Join(dirPath, filename)
+
+	// Check for duplicate filename (same time and title)
+	if _, err := os.Stat(filePath); err == nil {
+		// File exists, add a suffix
+		for i := 2; i < 100; i++ {
+			altFilePath := filepath.Join(dirPath, model.DuplicateFilename(filename, i))
+			if _, err := os.Stat(altFilePath); os.IsNotExist(err) {
+				filePath = altFilePath
+				break
+			}
+		}
+	}
+
+	if err := s.writeEvent(filePath, event); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// storedFilename returns the filename an event is saved under
+func storedFilename(event *model.Event) string {
+	if event.InSeries() {
+		return event.SeriesFilename()
+	}
+	if event.IsMultiDay() {
+		return event.SpanFilename()
+	}
+	return event.GenerateFilename()
+}
+
+// readEventFile reads and parses a single event file
+func (s *FileStore) readEventFile(filePath string) (*model.Event, error) {
+	filename := filepath.Base(filePath)
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	rel, err := filepath.Rel(s.root, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	event, err := s.decodeEvent(rel, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+	return event, nil
+}
+
+// parseStoredEvent parses content as the event file at rel, a path
+// relative to the store root such as "spans/<name>"
+func parseStoredEvent(rel, content string) (*model.Event, error) {
+	parse := model.ParseEventFromFilename
+	switch filepath.Dir(rel) {
+	case "spans":
+		parse = model.ParseSpanFromFilename
+	case "recurring":
+		parse = model.ParseSeriesFromFilename
+	}
+	return parse(filepath.Base(rel), content)
+}
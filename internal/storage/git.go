@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"bubblecal/internal/model"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gitIgnore keeps the undo log, the trash and half-written files out of
+// the history
+const gitIgnore = `# Written by bubblecal
+undo.json
+trash/
+.*
+!.gitignore
+`
+
+// GitStore keeps the calendar directory in a git repository. It describes
+// the writes made through it, and Commit commits everything changed since
+// the last commit under those descriptions, such as
+// "update: Team Standup 2025-08-13". Commit is called once per change, like
+// UndoLog.Seal, so an edit that takes several writes is one commit.
+type GitStore struct {
+	store Store
+	files *FileStore
+	args  []string // passed before every git command
+
+	mu      sync.Mutex
+	pending []string // descriptions of the writes since the last commit
+}
+
+// EventVersion is an event as one commit left it
+type EventVersion struct {
+	Hash    string
+	When    time.Time
+	Message string
+	Date    time.Time // the date to save the event on to restore it
+	Event   *model.Event
+}
+
+var _ Store = (*GitStore)(nil)
+
+// NewGitStore wraps store, committing the directory of files, which store
+// writes to. The repository is created if the directory isn't one yet.
+func NewGitStore(store Store, files *FileStore) (*GitStore, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git history needs git: %w", err)
+	}
+	g := &GitStore{store: store, files: files}
+	if err := os.MkdirAll(files.Root(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create calendar directory: %w", err)
+	}
+
+	// Commit as bubblecal unless git knows who the user is
+	if out, err := g.git("config", "user.email"); err != nil || strings.TrimSpace(out) == "" {
+		g.args = []string{"-c", "user.name=bubblecal", "-c", "user.email=bubblecal@localhost"}
+	}
+
+	// The calendar may sit inside another repository, such as a home
+	// directory kept in git; it gets its own
+	top, err := g.git("rev-parse", "--show-toplevel")
+	root, _ := filepath.EvalSymlinks(files.Root())
+	if resolved, _ := filepath.EvalSymlinks(strings.TrimSpace(top)); err == nil && resolved == root {
+		return g, nil
+	}
+	if _, err := g.git("init", "-q"); err != nil {
+		return nil, err
+	}
+	ignorePath := filepath.Join(files.Root(), ".gitignore")
+	if _, err := os.Stat(ignorePath); os.IsNotExist(err) {
+		if err := os.WriteFile(ignorePath, []byte(gitIgnore), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write .gitignore: %w", err)
+		}
+	}
+	g.pending = []string{"start history"}
+	if err := g.Commit(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// git runs git in the calendar directory and returns its output
+func (g *GitStore) git(args ...string) (string, error) {
+	cmd := exec.Command("git", append(append([]string(nil), g.args...), args...)...)
+	cmd.Dir = g.files.Root()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return string(out), fmt.Errorf("git %s: %s", args[0], msg)
+	}
+	return string(out), nil
+}
+
+// LoadDayEvents reads from the wrapped store
+func (g *GitStore) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	return g.store.LoadDayEvents(date)
+}
+
+// LoadRange reads from the wrapped store
+func (g *GitStore) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	return g.store.LoadRange(from, to)
+}
+
+// LoadSeries reads from the wrapped store
+func (g *GitStore) LoadSeries(id string) (*model.Event, []*model.Event, error) {
+	return g.store.LoadSeries(id)
+}
+
+// SaveEvent saves event and describes it for the next commit
+func (g *GitStore) SaveEvent(date time.Time, event *model.Event) error {
+	if err := g.store.SaveEvent(date, event); err != nil {
+		return err
+	}
+	g.describe("add", event, date)
+	return nil
+}
+
+// UpdateEvent updates an event and describes it for the next commit
+func (g *GitStore) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	if err := g.store.UpdateEvent(date, oldEvent, newEvent); err != nil {
+		return err
+	}
+	g.describe("update", newEvent, date)
+	return nil
+}
+
+// MoveEvent moves an event to another day and describes it for the next
+// commit
+func (g *GitStore) MoveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error {
+	if err := moveEvent(g.store, from, to, oldEvent, newEvent); err != nil {
+		return err
+	}
+	g.describe("update", newEvent, to)
+	return nil
+}
+
+// DeleteEvent deletes an event and describes it for the next commit
+func (g *GitStore) DeleteEvent(date time.Time, event *model.Event) error {
+	if err := g.store.DeleteEvent(date, event); err != nil {
+		return err
+	}
+	g.describe("delete", event, date)
+	return nil
+}
+
+// DiscardEvent deletes an event without keeping it in the trash and
+// describes it for the next commit
+func (g *GitStore) DiscardEvent(date time.Time, event *model.Event) error {
+	if err := discardEvent(g.store, date, event); err != nil {
+		return err
+	}
+	g.describe("delete", event, date)
+	return nil
+}
+
+func (g *GitStore) describe(verb string, event *model.Event, date time.Time) {
+	day := dayKey(date)
+	if event.StartDate != "" {
+		day = event.StartDate
+	}
+	title := event.Title
+	if g.files.Encrypted() {
+		title = "event" // keep titles out of the log
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.pending = append(g.pending, fmt.Sprintf("%s: %s %s", verb, title, day))
+}
+
+// Pending reports whether anything was written through the store since
+// the last commit
+func (g *GitStore) Pending() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.pending) > 0
+}
+
+// Commit commits the directory if anything was written through the store
+// since the last commit. Files changed some other way are committed along
+// with the next write. The files are staged under the directory lock, so
+// no write is committed half done.
+func (g *GitStore) Commit() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.pending) == 0 {
+		return nil
+	}
+	pending := g.pending
+	g.pending = nil
+
+	release, err := LockDir(g.files.Root())
+	if err != nil {
+		return err
+	}
+	_, err = g.git("add", "-A")
+	release()
+	if err != nil {
+		return err
+	}
+	if _, err := g.git("diff", "--cached", "--quiet"); err == nil {
+		return nil // the writes cancelled out
+	}
+	message := pending[0]
+	if len(pending) > 1 {
+		message = fmt.Sprintf("%s (+%d more)\n\n%s", pending[0], len(pending)-1, strings.Join(pending, "\n"))
+	}
+	_, err = g.git("commit", "-q", "--no-verify", "-m", message)
+	return err
+}
+
+// History returns the versions of the event with id shown on date, newest
+// first, following its file through renames
+func (g *GitStore) History(date time.Time, id string) ([]*EventVersion, error) {
+	// Finding the file may finish an interrupted update
+	unlock := g.files.readLock()
+	path, err := g.files.findEventFile(date, id)
+	unlock()
+	if err != nil {
+		return nil, err
+	}
+	rel, err := filepath.Rel(g.files.Root(), path)
+	if err != nil {
+		return nil, err
+	}
+	out, err := g.git("log", "--follow", "--name-only", "--format=%x1e%H%x1f%at%x1f%s", "--", filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*EventVersion
+	for _, record := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		fields := strings.Split(lines[0], "\x1f")
+		if len(fields) != 3 || len(lines) < 2 {
+			continue
+		}
+		file := strings.TrimSpace(lines[len(lines)-1])
+		seconds, _ := strconv.ParseInt(fields[1], 10, 64)
+		v := &EventVersion{Hash: fields[0], When: time.Unix(seconds, 0), Message: fields[2]}
+
+		content, err := g.git("show", v.Hash+":"+file)
+		if err != nil {
+			continue // deleted in this commit
+		}
+		if v.Event, err = g.files.decodeEvent(filepath.FromSlash(file), []byte(content)); err != nil {
+			continue
+		}
+		v.Date = versionDate(file, v.Event)
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// versionDate returns the day an event file at rel was stored on: its day
+// directory, or the start of a multi-day or recurring event
+func versionDate(rel string, event *model.Event) time.Time {
+	key := event.StartDate
+	if dir := filepath.ToSlash(filepath.Dir(rel)); strings.HasPrefix(dir, "days/") {
+		key = strings.TrimPrefix(dir, "days/")
+	}
+	date, _ := time.ParseInLocation(model.DateFormat, key, time.Local)
+	return date
+}
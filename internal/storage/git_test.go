@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitCommitWaitsForWrites(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("needs git")
+	}
+	files := NewFileStore(t.TempDir())
+	git, err := NewGitStore(files, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := git.SaveEvent(testDate, &model.Event{StartTime: "09:00", Title: "Standup"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another writer holds the lock, so nothing is staged until it is done
+	release, err := LockDir(files.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error)
+	go func() { done <- git.Commit() }()
+	select {
+	case <-done:
+		t.Fatal("committed while the directory was locked")
+	case <-time.After(100 * time.Millisecond):
+	}
+	release()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if git.Pending() {
+		t.Error("still pending after the commit")
+	}
+	if out, _ := git.git("log", "--format=%s"); !strings.HasPrefix(out, "add: Standup 2025-08-13") {
+		t.Errorf("log is %q", out)
+	}
+}
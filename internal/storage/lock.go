@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Every bubblecal process, and any script that wants to play along, takes
+// an advisory lock on <root>/.lock while it writes, so two writers never
+// interleave the steps of a save. Before writing, a store also checks that
+// the files it is about to change are as it last read them; if another
+// program changed them in the meantime, the write is refused with
+// ErrConflict rather than overwriting that change.
+
+// lockName is the file in a data directory that writers lock
+const lockName = ".lock"
+
+// ErrConflict is returned for writes refused because another program
+// changed the calendar since it was read
+var ErrConflict = errors.New("not saved, so as not to overwrite it; try again once it has reloaded")
+
+// LockDir waits for the write lock on the data directory root and returns
+// the function that releases it
+func LockDir(root string) (func(), error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create calendar directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(root, lockName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := flock(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock calendar directory: %w", err)
+	}
+	// Closing the file releases the lock
+	return func() { f.Close() }, nil
+}
+
+// versions remembers how the files and directories a store read looked
+// at the time, to tell whether someone else has changed them since
+type versions struct {
+	mu   sync.Mutex
+	seen map[string]string
+}
+
+func newVersions() *versions {
+	return &versions{seen: make(map[string]string)}
+}
+
+// stamp describes path's size and modification time, or "-" if it
+// doesn't exist
+func stamp(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano())
+}
+
+// record notes how paths look now
+func (v *versions) record(paths ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, path := range paths {
+		v.seen[path] = stamp(path)
+	}
+}
+
+// changed returns the first of paths that changed since it was recorded,
+// or "" if none did. Paths never recorded haven't been read, so they
+// can't be overwritten unseen.
+func (v *versions) changed(paths ...string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, path := range paths {
+		if old, ok := v.seen[path]; ok && old != stamp(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// read calls read, stamping paths first so that a change made by another
+// program while they are read is caught by the next write. Reading may
+// change them itself, by recovering or rewriting files, in which case
+// they are read again as it left them.
+func (v *versions) read(paths []string, read func() error) error {
+	for attempt := 0; ; attempt++ {
+		v.record(paths...)
+		if err := read(); err != nil || attempt == 2 || v.changed(paths...) == "" {
+			return err
+		}
+	}
+}
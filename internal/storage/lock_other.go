@@ -0,0 +1,11 @@
+//go:build !unix
+
+package storage
+
+import "os"
+
+// flock does nothing where flock(2) isn't available; writers are only
+// kept apart within one process
+func flock(f *os.File) error {
+	return nil
+}
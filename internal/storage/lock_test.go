@@ -0,0 +1,97 @@
+//go:build unix
+
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadWaitsForWriter(t *testing.T) {
+	root := t.TempDir()
+	s := NewFileStore(root)
+	if err := s.SaveEvent(testDate, &model.Event{StartTime: "09:00", Title: "Standup"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process is halfway through writing a file
+	unlock, err := LockDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	temp := filepath.Join(s.DayDirPath(testDate), tempPrefix+"123")
+	if err := os.WriteFile(temp, []byte("half"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := make(chan string)
+	go func() {
+		events, _ := NewFileStore(root).LoadDayEvents(testDate)
+		title := ""
+		for _, e := range events {
+			title += e.Title
+		}
+		loaded <- title
+	}()
+	select {
+	case <-loaded:
+		t.Fatal("loaded while another process was writing")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := os.Stat(temp); err != nil {
+		t.Errorf("the writer's temporary file was removed: %v", err)
+	}
+
+	// The writer crashed without renaming its file
+	unlock()
+	if title := <-loaded; title != "Standup" {
+		t.Errorf("loaded %q", title)
+	}
+	if _, err := os.Stat(temp); !os.IsNotExist(err) {
+		t.Errorf("the crashed writer's temporary file is still there")
+	}
+}
+
+func TestLoadRewriteIsNotAConflict(t *testing.T) {
+	root := t.TempDir()
+	s := NewFileStore(root)
+	// Written by hand, without an ID, so loading rewrites it
+	dir := s.DayDirPath(testDate)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "0900-1000-Standup"), []byte("Standup\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events, err := s.LoadDayEvents(testDate)
+	if err != nil || len(events) != 1 || events[0].ID == "" {
+		t.Fatalf("loaded %v, %v", events, err)
+	}
+	if err := s.SaveEvent(testDate, &model.Event{StartTime: "11:00", Title: "Review"}); err != nil {
+		t.Errorf("saving after the load: %v", err)
+	}
+}
+
+func TestChangeWhileReadingIsAConflict(t *testing.T) {
+	s := NewFileStore(t.TempDir())
+	if err := s.SaveEvent(testDate, &model.Event{StartTime: "09:00", Title: "Standup"}); err != nil {
+		t.Fatal(err)
+	}
+	// Another program keeps changing the day as it is read
+	dir := s.DayDirPath(testDate)
+	n := 0
+	err := s.seen.read(s.eventDirs(testDate), func() error {
+		n++
+		return os.WriteFile(filepath.Join(dir, "1000-1100-Edit_"+string(rune('a'+n))), []byte("Edit\n"), 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveEvent(testDate, &model.Event{StartTime: "11:00", Title: "Review"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("saving over a change made while reading: %v, want ErrConflict", err)
+	}
+}
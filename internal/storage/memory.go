@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps events in memory only. It is useful for exercising
+// the views without touching the home directory.
+type MemoryStore struct {
+	mu     sync.Mutex
+	days   map[string][]*model.Event
+	spans  []*model.Event // multi-day events, stored once
+	series []*model.Event // recurring series and their overrides, expanded on load
+	tasks  []*model.Task
+	notes  map[string]string // day notes by date
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{days: make(map[string][]*model.Event)}
+}
+
+// LoadDayEvents returns copies of the events stored for a date
+func (s *MemoryStore) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.days[dayKey(date)]
+	events := make([]*model.Event, 0, len(stored))
+	for _, evt := range stored {
+		events = append(events, evt.Clone())
+	}
+	for _, span := range s.spans {
+		if span.Covers(date) {
+			events = append(events, span.Clone())
+		}
+	}
+	events = append(events, expandSeries(s.series, date)...)
+	sortEvents(events, date)
+	return events, nil
+}
+
+// LoadRange returns copies of the events between from and to
+func (s *MemoryStore) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	return loadRange(from, to, s.LoadDayEvents)
+}
+
+// SaveEvent stores a copy of event on date, assigning an ID if needed
+func (s *MemoryStore) SaveEvent(date time.Time, event *model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.ID == "" {
+		event.ID = model.NewID()
+	}
+	prepareEvent(date, event)
+	s.insert(date, event)
+	return nil
+}
+
+// UpdateEvent replaces the event with oldEvent's ID by newEvent
+func (s *MemoryStore) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	return s.MoveEvent(date, date, oldEvent, newEvent)
+}
+
+// MoveEvent replaces an event on from by newEvent on to
+func (s *MemoryStore) MoveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.remove(from, oldEvent)
+	if stored == nil {
+		return fmt.Errorf("event not found")
+	}
+	newEvent.ID = oldEvent.ID
+	prepareEvent(to, newEvent)
+	rebaseSeries(stored, oldEvent, newEvent)
+	keepExtra(stored, newEvent)
+	s.insert(to, newEvent)
+	return nil
+}
+
+// DeleteEvent removes an event from a date
+func (s *MemoryStore) DeleteEvent(date time.Time, event *model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.remove(date, event) == nil {
+		return fmt.Errorf("event not found")
+	}
+	return nil
+}
+
+// LoadSeries returns copies of a recurring series and its overrides
+func (s *MemoryStore) LoadSeries(id string) (*model.Event, []*model.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, overrides, err := findSeries(s.series, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	series = series.Clone()
+	for i, o := range overrides {
+		overrides[i] = o.Clone()
+	}
+	return series, overrides, nil
+}
+
+// insert stores a copy of event; callers hold s.mu
+func (s *MemoryStore) insert(date time.Time, event *model.Event) {
+	if event.InSeries() {
+		s.series = append(s.series, event.Clone())
+		return
+	}
+	if event.IsMultiDay() {
+		s.spans = append(s.spans, event.Clone())
+		return
+	}
+	key := dayKey(date)
+	s.days[key] = append(s.days[key], event.Clone())
+}
+
+// remove deletes the stored copy of event from date, the spans or the
+// series and returns it, or nil if it wasn't found; callers hold s.mu
+func (s *MemoryStore) remove(date time.Time, event *model.Event) *model.Event {
+	key := dayKey(date)
+	for i, evt := range s.days[key] {
+		if sameEvent(evt, event) {
+			s.days[key] = append(s.days[key][:i], s.days[key][i+1:]...)
+			if len(s.days[key]) == 0 {
+				delete(s.days, key)
+			}
+			return evt
+		}
+	}
+	for _, list := range []*[]*model.Event{&s.spans, &s.series} {
+		for i, evt := range *list {
+			if sameEvent(evt, event) {
+				*list = append((*list)[:i], (*list)[i+1:]...)
+				return evt
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Migrated counts what Migrate copied
+type Migrated struct {
+	Events int
+	Notes  int
+	Tasks  int
+}
+
+// datedEvent is an event as stored, with the date it is stored on
+type datedEvent struct {
+	date  time.Time
+	event *model.Event
+}
+
+// dumper is implemented by the stores Migrate can copy between
+type dumper interface {
+	Store
+	NoteStore
+	TaskStore
+	Root() string
+	// dump returns every stored event, unexpanded, and the dates that
+	// have a note
+	dump() ([]datedEvent, []time.Time, error)
+	// files returns the paths of the store's event, note and task files
+	// and directories, relative to its root
+	files() ([]string, error)
+}
+
+var (
+	_ dumper = (*FileStore)(nil)
+	_ dumper = (*TextStore)(nil)
+)
+
+// Migrate copies every event, note and task of from into to, which must
+// be empty. Events keep their IDs and everything stored with them. Both
+// stores must be a FileStore or a TextStore; from is left as it was, to
+// be put away with Archive once the copy is in use.
+func Migrate(from, to Store) (Migrated, error) {
+	var n Migrated
+	src, ok1 := from.(dumper)
+	dst, ok2 := to.(dumper)
+	if !ok1 || !ok2 {
+		return n, fmt.Errorf("can only migrate between the directory and text formats")
+	}
+
+	events, notes, err := dst.dump()
+	if err != nil {
+		return n, err
+	}
+	tasks, err := dst.LoadTasks()
+	if err != nil {
+		return n, err
+	}
+	if len(events) > 0 || len(notes) > 0 || len(tasks) > 0 {
+		return n, fmt.Errorf("the target already holds events, notes or tasks")
+	}
+
+	if events, notes, err = src.dump(); err != nil {
+		return n, err
+	}
+	if tasks, err = src.LoadTasks(); err != nil {
+		return n, err
+	}
+	for _, e := range events {
+		if err := dst.SaveEvent(e.date, e.event); err != nil {
+			return n, fmt.Errorf("failed to copy %s: %w", e.event.Title, err)
+		}
+		n.Events++
+	}
+	for _, date := range notes {
+		note, err := src.LoadNote(date)
+		if err != nil {
+			return n, err
+		}
+		if err := dst.SaveNote(date, note); err != nil {
+			return n, err
+		}
+		n.Notes++
+	}
+	for _, task := range tasks {
+		if err := dst.SaveTask(task); err != nil {
+			return n, fmt.Errorf("failed to copy task %s: %w", task.Title, err)
+		}
+		n.Tasks++
+	}
+	return n, nil
+}
+
+// dump reads every day directory, spans/ and recurring/
+func (s *FileStore) dump() ([]datedEvent, []time.Time, error) {
+	unlock := s.readLock()
+	defer unlock()
+	var events []datedEvent
+	var notes []time.Time
+	days, err := os.ReadDir(s.daysDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read days directory: %w", err)
+	}
+	for _, day := range days {
+		date, err := time.ParseInLocation(model.DateFormat, day.Name(), time.Local)
+		if err != nil || !day.IsDir() {
+			continue
+		}
+		loaded, err := s.loadDir(s.DayDirPath(date), func() error {
+			return s.recoverDay(day.Name())
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, event := range loaded {
+			events = append(events, datedEvent{date, event})
+		}
+		if _, err := os.Stat(s.notePath(date)); err == nil {
+			notes = append(notes, date)
+		}
+	}
+	for _, dir := range []string{s.spansDir(), s.recurringDir()} {
+		loaded, err := s.loadDir(dir, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, event := range loaded {
+			date, _ := time.ParseInLocation(model.DateFormat, event.StartDate, time.Local)
+			events = append(events, datedEvent{date, event})
+		}
+	}
+	return events, notes, nil
+}
+
+// dump reads every day's file, spans.txt and recurring.txt
+func (s *TextStore) dump() ([]datedEvent, []time.Time, error) {
+	// Reading may give events IDs
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	var events []datedEvent
+	var notes []time.Time
+	entries, err := os.ReadDir(s.root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to read calendar directory: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		date, err := time.ParseInLocation(model.DateFormat, strings.TrimSuffix(name, ext), time.Local)
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		switch ext {
+		case ".md":
+			notes = append(notes, date)
+		case ".txt":
+			f, err := s.load(s.dayPath(date), false)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, event := range f.events {
+				events = append(events, datedEvent{date, event})
+			}
+		}
+	}
+	for _, path := range []string{s.spansPath(), s.recurringPath()} {
+		f, err := s.load(path, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, event := range f.events {
+			date, _ := time.ParseInLocation(model.DateFormat, event.StartDate, time.Local)
+			events = append(events, datedEvent{date, event})
+		}
+	}
+	return events, notes, nil
+}
+
+// Archive moves the event, note and task files of store, a FileStore or a
+// TextStore, into dir, keeping their paths, so that the store is empty
+// and a later Migrate can copy back into it
+func Archive(store Store, dir string) error {
+	s, ok := store.(dumper)
+	if !ok {
+		return fmt.Errorf("can only archive the directory and text formats")
+	}
+	root := s.Root()
+	unlock, err := LockDir(root)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+	for _, rel := range files {
+		target := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		if err := os.Rename(filepath.Join(root, rel), target); err != nil {
+			return fmt.Errorf("failed to move %s: %w", rel, err)
+		}
+	}
+	return nil
+}
+
+// files lists days/, spans/, recurring/ and tasks/; the trash stays
+func (s *FileStore) files() ([]string, error) {
+	var files []string
+	for _, dir := range []string{s.daysDir(), s.spansDir(), s.recurringDir(), s.tasksDir()} {
+		if exists(dir) {
+			files = append(files, filepath.Base(dir))
+		}
+	}
+	return files, nil
+}
+
+// files lists the day, note, spans and recurring files and tasks/
+func (s *TextStore) files() ([]string, error) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read calendar directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		_, err := time.ParseInLocation(model.DateFormat, strings.TrimSuffix(name, ext), time.Local)
+		switch {
+		case entry.IsDir():
+			if name == "tasks" {
+				files = append(files, name)
+			}
+		case err == nil && (ext == ".txt" || ext == ".md"),
+			name == filepath.Base(s.spansPath()), name == filepath.Base(s.recurringPath()):
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"testing"
+)
+
+func TestMigrateRoundTrip(t *testing.T) {
+	from := NewFileStore(t.TempDir())
+	var saved []*model.Event
+	for _, title := range []string{
+		"Team Standup",
+		" padded title ",
+		"  indented",
+		"trailing\t",
+		`"quoted" talk`,
+		`"quoted"`,
+		"[draft] plan",
+		"two\nlines",
+	} {
+		event := &model.Event{StartTime: "09:00", EndTime: "10:00", Title: title, Location: "Room 4"}
+		if err := from.SaveEvent(testDate, event); err != nil {
+			t.Fatalf("%q: %v", title, err)
+		}
+		saved = append(saved, event)
+	}
+	if err := from.SaveNote(testDate, "Keep me"); err != nil {
+		t.Fatal(err)
+	}
+
+	text := NewTextStore(t.TempDir())
+	if _, err := Migrate(from, text); err != nil {
+		t.Fatal(err)
+	}
+	back := NewFileStore(t.TempDir())
+	if _, err := Migrate(text, back); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := back.LoadDayEvents(testDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID := map[string]*model.Event{}
+	for _, e := range events {
+		byID[e.ID] = e
+	}
+	for _, want := range saved {
+		got, ok := byID[want.ID]
+		if !ok {
+			t.Errorf("%q was lost", want.Title)
+			continue
+		}
+		if got.Title != want.Title || got.FormatFileContent() != want.FormatFileContent() {
+			t.Errorf("got %q %q, want %q %q", got.Title, got.FormatFileContent(), want.Title, want.FormatFileContent())
+		}
+	}
+	if len(events) != len(saved) {
+		t.Errorf("got %d events back, want %d", len(events), len(saved))
+	}
+	if note, _ := back.LoadNote(testDate); note != "Keep me" {
+		t.Errorf("note is %q", note)
+	}
+}
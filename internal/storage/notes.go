@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// NoteFilename is the file in a day directory holding the day's note.
+// Like every name starting with "_", it is never read as an event.
+const NoteFilename = "_note.md"
+
+// isReservedName reports whether a directory entry holds something other
+// than an event
+func isReservedName(name string) bool {
+	return strings.HasPrefix(name, "_")
+}
+
+// NoteStore reads and writes free-form notes, one per day. Stores that
+// keep notes implement it next to Store.
+type NoteStore interface {
+	// LoadNote returns the note for a date, or "" if there is none
+	LoadNote(date time.Time) (string, error)
+	// SaveNote replaces the note for a date; an empty note removes it
+	SaveNote(date time.Time, note string) error
+}
+
+var (
+	_ NoteStore = (*FileStore)(nil)
+	_ NoteStore = (*MemoryStore)(nil)
+)
+
+func (s *FileStore) notePath(date time.Time) string {
+	return filepath.Join(s.DayDirPath(date), NoteFilename)
+}
+
+// LoadNote reads the note file of a day directory
+func (s *FileStore) LoadNote(date time.Time) (string, error) {
+	data, err := s.readFile(s.notePath(date))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read note: %w", err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// SaveNote writes the note file of a day directory, removing it (and the
+// directory, if nothing else is left) when the note is empty
+func (s *FileStore) SaveNote(date time.Time, note string) error {
+	unlock, err := s.lock(date)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	note = strings.TrimRight(note, "\n\r\t ")
+	if note == "" {
+		if err := os.Remove(s.notePath(date)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete note: %w", err)
+		}
+		dirPath := s.DayDirPath(date)
+		if entries, _ := os.ReadDir(dirPath); len(entries) == 0 {
+			os.Remove(dirPath)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(s.DayDirPath(date), 0755); err != nil {
+		return fmt.Errorf("failed to create day directory: %w", err)
+	}
+	if err := s.writeFile(s.notePath(date), []byte(note+"\n")); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+	return nil
+}
+
+// LoadNote returns the note stored for a date
+func (s *MemoryStore) LoadNote(date time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notes[dayKey(date)], nil
+}
+
+// SaveNote stores the note for a date
+func (s *MemoryStore) SaveNote(date time.Time, note string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note = strings.TrimRight(note, "\n\r\t ")
+	if note == "" {
+		delete(s.notes, dayKey(date))
+		return nil
+	}
+	if s.notes == nil {
+		s.notes = make(map[string]string)
+	}
+	s.notes[dayKey(date)] = note
+	return nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"time"
+)
+
+// Recurring series are stored once, like multi-day events, and expanded
+// into occurrences whenever a day is loaded. Each occurrence is a copy
+// of the series with RecurrenceID set to the date it was generated for
+// and StartDate/EndDate moved to that occurrence; it keeps the series ID,
+// so updating or deleting an occurrence changes the whole series.
+//
+// Occurrences that were edited on their own are stored next to the series
+// as overrides: plain events with SeriesID and RecurrenceID set, whose
+// original date is listed in the series' ExDates. See series.go.
+
+// expandSeries returns the occurrences of series that cover date, plus
+// any overrides among them that fall on date
+func expandSeries(series []*model.Event, date time.Time) []*model.Event {
+	var occurrences []*model.Event
+	for _, s := range series {
+		if s.IsOverride() && !s.IsRecurring() {
+			if s.StartDate == dayKey(date) || s.Covers(date) {
+				occurrences = append(occurrences, s.Clone())
+			}
+			continue
+		}
+		if occ := s.OccurrenceOn(date); occ != nil {
+			occurrences = append(occurrences, occ)
+		}
+	}
+	return occurrences
+}
+
+// prepareSeries fills in the first date of a new recurring series or
+// override
+func prepareSeries(date time.Time, event *model.Event) {
+	if event.InSeries() && event.StartDate == "" {
+		event.StartDate = dayKey(date)
+	}
+	if !event.IsOverride() {
+		event.RecurrenceID = ""
+	}
+}
+
+// rebaseSeries keeps a series anchored when it is updated through one of
+// its occurrences: the series start moves by as many days as the edited
+// occurrence did, instead of jumping to the occurrence's date
+func rebaseSeries(stored, oldEvent, newEvent *model.Event) {
+	if oldEvent.RecurrenceID == "" || !newEvent.IsRecurring() || !stored.IsRecurring() {
+		return
+	}
+	oldStart, err1 := time.Parse(model.DateFormat, oldEvent.StartDate)
+	newStart, err2 := time.Parse(model.DateFormat, newEvent.StartDate)
+	seriesStart, err3 := time.Parse(model.DateFormat, stored.StartDate)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return
+	}
+
+	length := newEvent.SpanDays()
+	start := seriesStart.AddDate(0, 0, int(newStart.Sub(oldStart).Hours()/24))
+	newEvent.StartDate = start.Format(model.DateFormat)
+	if newEvent.IsMultiDay() {
+		newEvent.EndDate = start.AddDate(0, 0, length-1).Format(model.DateFormat)
+	}
+}
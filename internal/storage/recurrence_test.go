@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"strings"
+	"testing"
+)
+
+func TestExpandSeriesWithOverrides(t *testing.T) {
+	rule, _ := model.ParseRecurrence("FREQ=DAILY;COUNT=5")
+	series := &model.Event{
+		ID: "s", StartTime: "09:00", EndTime: "09:15", Title: "Standup", StartDate: "2025-08-11",
+		Recurrence: rule, ExDates: []string{"2025-08-12", "2025-08-13"},
+	}
+	// The 12th moved to 16:00 and the 13th to the 20th
+	overrides := []*model.Event{
+		{ID: "o1", StartTime: "16:00", Title: "Late Standup", SeriesID: "s", RecurrenceID: "2025-08-12", StartDate: "2025-08-12"},
+		{ID: "o2", StartTime: "09:00", Title: "Standup", SeriesID: "s", RecurrenceID: "2025-08-13", StartDate: "2025-08-20"},
+	}
+	stored := append([]*model.Event{series}, overrides...)
+
+	tests := []struct {
+		date string
+		want string // "<time> <recurrence-id>" of each event, sorted
+	}{
+		{"2025-08-10", ""},
+		{"2025-08-11", "09:00 2025-08-11"},
+		{"2025-08-12", "16:00 2025-08-12"},
+		{"2025-08-13", ""},
+		{"2025-08-15", "09:00 2025-08-15"},
+		{"2025-08-16", ""}, // past the fifth occurrence
+		{"2025-08-20", "09:00 2025-08-13"},
+	}
+	for _, tt := range tests {
+		events := expandSeries(stored, date(tt.date))
+		sortEvents(events, date(tt.date))
+		var got []string
+		for _, e := range events {
+			got = append(got, e.StartTime+" "+e.RecurrenceID)
+		}
+		if strings.Join(got, ", ") != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.date, strings.Join(got, ", "), tt.want)
+		}
+	}
+}
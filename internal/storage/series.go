@@ -0,0 +1,233 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"fmt"
+	"time"
+)
+
+// Scope says which occurrences of a recurring series an edit or delete
+// applies to
+type Scope int
+
+const (
+	ScopeThis      Scope = iota // only the selected occurrence
+	ScopeFollowing              // the selected occurrence and every later one
+	ScopeAll                    // the whole series
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeThis:
+		return "This event"
+	case ScopeFollowing:
+		return "This and following events"
+	default:
+		return "All events"
+	}
+}
+
+// findSeries picks the series with the given ID and its overrides out of
+// the contents of a recurring directory
+func findSeries(events []*model.Event, id string) (*model.Event, []*model.Event, error) {
+	var series *model.Event
+	var overrides []*model.Event
+	for _, evt := range events {
+		switch {
+		case evt.ID == id && evt.IsRecurring():
+			series = evt
+		case evt.SeriesID == id:
+			overrides = append(overrides, evt)
+		}
+	}
+	if series == nil {
+		return nil, nil, fmt.Errorf("series not found")
+	}
+	return series, overrides, nil
+}
+
+// UpdateOccurrence applies newEvent to occ, an occurrence or override of a
+// recurring series shown on date, within scope:
+//
+//   - ScopeThis stores newEvent as an override and excludes the original
+//     date from the series
+//   - ScopeFollowing ends the series before occ and starts a new series
+//     from newEvent; overrides from occ on are dropped
+//   - ScopeAll updates the series itself
+func UpdateOccurrence(store Store, date time.Time, occ, newEvent *model.Event, scope Scope) error {
+	series, overrides, err := store.LoadSeries(occ.SeriesKey())
+	if err != nil {
+		return err
+	}
+	occDate, err := time.ParseInLocation(model.DateFormat, occ.RecurrenceID, time.Local)
+	if err != nil {
+		return fmt.Errorf("event is not an occurrence of a series")
+	}
+	if scope == ScopeFollowing && occ.RecurrenceID <= series.StartDate {
+		scope = ScopeAll
+	}
+
+	switch scope {
+	case ScopeThis:
+		newEvent.Recurrence = nil
+		newEvent.ExDates = nil
+		newEvent.SeriesID = series.ID
+		newEvent.RecurrenceID = occ.RecurrenceID
+		if newEvent.StartDate == "" {
+			newEvent.StartDate = dayKey(date)
+		}
+		if occ.IsOverride() {
+			return store.UpdateEvent(date, occ, newEvent)
+		}
+		newEvent.ID = ""
+		if err := excludeDate(store, date, series, occ.RecurrenceID); err != nil {
+			return err
+		}
+		return store.SaveEvent(date, newEvent)
+
+	case ScopeFollowing:
+		if err := truncateSeries(store, date, series, overrides, occDate); err != nil {
+			return err
+		}
+		if newEvent.IsRecurring() {
+			kept := len(series.Recurrence.Occurrences(seriesStart(series), seriesStart(series), occDate.AddDate(0, 0, -1)))
+			if rule := newEvent.Recurrence; rule.Count > 0 && rule.Count == series.Recurrence.Count {
+				rule.Count -= kept
+				if rule.Count < 1 {
+					rule.Count = 1
+				}
+			}
+		}
+		newEvent.ID = ""
+		newEvent.ExDates = nil
+		newEvent.SeriesID = ""
+		newEvent.RecurrenceID = ""
+		return store.SaveEvent(date, newEvent)
+
+	default:
+		// Move the series by as many days as this occurrence moved
+		anchor := occ.Clone()
+		anchor.ID = series.ID
+		anchor.StartDate = occ.RecurrenceID
+		rebaseSeries(series, anchor, newEvent)
+		if newEvent.IsRecurring() {
+			newEvent.ExDates = shiftDates(series.ExDates, series.StartDate, newEvent.StartDate)
+		} else {
+			// No longer recurring: the overrides have nothing to override
+			if err := deleteOverrides(store, date, overrides, ""); err != nil {
+				return err
+			}
+		}
+		newEvent.SeriesID = ""
+		newEvent.RecurrenceID = ""
+		return store.UpdateEvent(date, series, newEvent)
+	}
+}
+
+// DeleteOccurrence deletes occ, an occurrence or override of a recurring
+// series shown on date, within scope
+func DeleteOccurrence(store Store, date time.Time, occ *model.Event, scope Scope) error {
+	series, overrides, err := store.LoadSeries(occ.SeriesKey())
+	if err != nil {
+		return err
+	}
+	occDate, err := time.ParseInLocation(model.DateFormat, occ.RecurrenceID, time.Local)
+	if err != nil {
+		return fmt.Errorf("event is not an occurrence of a series")
+	}
+	if scope == ScopeFollowing && occ.RecurrenceID <= series.StartDate {
+		scope = ScopeAll
+	}
+
+	switch scope {
+	case ScopeThis:
+		if occ.IsOverride() {
+			// The original date is already excluded
+			return store.DeleteEvent(date, occ)
+		}
+		return excludeDate(store, date, series, occ.RecurrenceID)
+
+	case ScopeFollowing:
+		return truncateSeries(store, date, series, overrides, occDate)
+
+	default:
+		if err := deleteOverrides(store, date, overrides, ""); err != nil {
+			return err
+		}
+		return store.DeleteEvent(date, series)
+	}
+}
+
+// excludeDate adds an occurrence date to the series' ExDates
+func excludeDate(store Store, date time.Time, series *model.Event, occurrence string) error {
+	updated := series.Clone()
+	if !updated.IsExcluded(mustParseDate(occurrence)) {
+		updated.ExDates = append(updated.ExDates, occurrence)
+	}
+	return store.UpdateEvent(date, series, updated)
+}
+
+// truncateSeries ends a series on the day before from and removes the
+// overrides of occurrences from then on
+func truncateSeries(store Store, date time.Time, series *model.Event, overrides []*model.Event, from time.Time) error {
+	if err := deleteOverrides(store, date, overrides, from.Format(model.DateFormat)); err != nil {
+		return err
+	}
+
+	start := seriesStart(series)
+	last := from.AddDate(0, 0, -1)
+	kept := series.Recurrence.Occurrences(start, start, last)
+	if len(kept) == 0 {
+		return store.DeleteEvent(date, series)
+	}
+
+	updated := series.Clone()
+	if updated.Recurrence.Count > 0 {
+		updated.Recurrence.Count = len(kept)
+	} else {
+		updated.Recurrence.Until = last.Format(model.DateFormat)
+	}
+	var exDates []string
+	for _, ex := range updated.ExDates {
+		if ex < from.Format(model.DateFormat) {
+			exDates = append(exDates, ex)
+		}
+	}
+	updated.ExDates = exDates
+	return store.UpdateEvent(date, series, updated)
+}
+
+// deleteOverrides deletes the overrides for occurrences on or after from
+// ("" for all of them)
+func deleteOverrides(store Store, date time.Time, overrides []*model.Event, from string) error {
+	for _, o := range overrides {
+		if from != "" && o.RecurrenceID < from {
+			continue
+		}
+		if err := store.DeleteEvent(date, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shiftDates moves dates by the number of days between from and to
+func shiftDates(dates []string, from, to string) []string {
+	days := int(mustParseDate(to).Sub(mustParseDate(from)).Hours() / 24)
+	shifted := make([]string, 0, len(dates))
+	for _, d := range dates {
+		shifted = append(shifted, mustParseDate(d).AddDate(0, 0, days).Format(model.DateFormat))
+	}
+	return shifted
+}
+
+func seriesStart(series *model.Event) time.Time {
+	return mustParseDate(series.StartDate)
+}
+
+// mustParseDate parses a date that was validated when it was loaded; a
+// malformed one yields the zero time
+func mustParseDate(date string) time.Time {
+	t, _ := time.Parse(model.DateFormat, date)
+	return t
+}
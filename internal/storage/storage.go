@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"bubblecal/internal/model"
@@ -9,6 +8,58 @@ import (
 	"time"
 )
 
+// Store is the interface the TUI uses to read and write events.
+// Implementations decide where and how events are persisted.
+type Store interface {
+	// LoadDayEvents returns the events for a single date, sorted
+	LoadDayEvents(date time.Time) ([]*model.Event, error)
+	// LoadRange returns the events for every date between from and to
+	// (inclusive), keyed by "2006-01-02". Dates without events are omitted.
+	LoadRange(from, to time.Time) (map[string][]*model.Event, error)
+	// SaveEvent adds a new event to a date
+	SaveEvent(date time.Time, event *model.Event) error
+	// UpdateEvent replaces oldEvent with newEvent on a date
+	UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error
+	// DeleteEvent removes an event from a date
+	DeleteEvent(date time.Time, event *model.Event) error
+	// LoadSeries returns the recurring series with the given ID and the
+	// overrides of its occurrences
+	LoadSeries(id string) (*model.Event, []*model.Event, error)
+}
+
+// Mover is implemented by stores that can move an event to another day
+// in one write, and the stores wrapping them
+type Mover interface {
+	// MoveEvent replaces oldEvent on from with newEvent on to
+	MoveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error
+}
+
+var (
+	_ Mover = (*FileStore)(nil)
+	_ Mover = (*TextStore)(nil)
+	_ Mover = (*MemoryStore)(nil)
+	_ Mover = (*CachedStore)(nil)
+	_ Mover = (*GitStore)(nil)
+	_ Mover = (*UndoLog)(nil)
+)
+
+// moveEvent replaces oldEvent on from with newEvent on to. Stores that
+// can't move events get the new copy saved before the old one is
+// discarded, which keeps the event out of the trash.
+func moveEvent(store Store, from, to time.Time, oldEvent, newEvent *model.Event) error {
+	if m, ok := store.(Mover); ok {
+		return m.MoveEvent(from, to, oldEvent, newEvent)
+	}
+	if dayKey(from) == dayKey(to) {
+		return store.UpdateEvent(from, oldEvent, newEvent)
+	}
+	newEvent.ID = oldEvent.ID
+	if err := store.SaveEvent(to, newEvent); err != nil {
+		return err
+	}
+	return discardEvent(store, from, oldEvent)
+}
+
 // GetCalendarDir returns the base directory for calendar data
 func GetCalendarDir() string {
 	home, err := os.UserHomeDir()
@@ -23,199 +74,75 @@ func GetDaysDir() string {
 	return filepath.Join(GetCalendarDir(), "days")
 }
 
-// GetDayDirPath returns the directory path for a specific date
-func GetDayDirPath(date time.Time) string {
-	dayDir := date.Format("2006-01-02")
-	return filepath.Join(GetDaysDir(), dayDir)
+// dayKey returns the map key / directory name used for a date
+func dayKey(date time.Time) string {
+	return date.Format("2006-01-02")
 }
 
-// LoadDayEvents loads events from a day directory
-func LoadDayEvents(date time.Time) ([]*model.Event, error) {
-	dirPath := GetDayDirPath(date)
-	
-	// If directory doesn't exist, return empty list (no events)
-	if _, err := os.Stat(dirPath); os.IsNotExist(err) {
-		return []*model.Event{}, nil
-	}
-	
-	// Read all files in the directory
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read day directory: %w", err)
-	}
-	
-	var events []*model.Event
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		
-		filename := entry.Name()
-		filePath := filepath.Join(dirPath, filename)
-		
-		// Read file content
-		content, err := os.ReadFile(filePath)
+// loadRange loads each day between from and to using loadDay
+func loadRange(from, to time.Time, loadDay func(time.Time) ([]*model.Event, error)) (map[string][]*model.Event, error) {
+	result := make(map[string][]*model.Event)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		events, err := loadDay(date)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to read %s: %v\n", filename, err)
-			continue
+			return nil, err
 		}
-		
-		// Parse event from filename and content
-		event, err := model.ParseEventFromFilename(filename, string(content))
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to parse %s: %v\n", filename, err)
-			continue
+		if len(events) > 0 {
+			result[dayKey(date)] = events
 		}
-		
-		events = append(events, event)
 	}
-	
-	// Sort events
-	sortEvents(events)
-	
-	return events, nil
+	return result, nil
 }
 
-// SaveDayEvents saves all events for a day (compatibility layer)
-// This clears existing events and saves all provided events
-func SaveDayEvents(date time.Time, events []*model.Event) error {
-	// First, clear existing events for this day
-	dirPath := GetDayDirPath(date)
-	if _, err := os.Stat(dirPath); err == nil {
-		// Directory exists, remove it to clear all events
-		if err := os.RemoveAll(dirPath); err != nil {
-			return fmt.Errorf("failed to clear existing events: %w", err)
-		}
-	}
-	
-	// Save each event
-	for _, event := range events {
-		if err := SaveEvent(date, event); err != nil {
-			return fmt.Errorf("failed to save event: %w", err)
-		}
-	}
-	
-	return nil
+// prepareEvent normalizes an event before it is stored on date
+func prepareEvent(date time.Time, event *model.Event) {
+	// An end time before the start time means the event ends the next day
+	event.SpanOvernight(date)
+	prepareSeries(date, event)
 }
 
-// SaveEvent saves a single event to its own file
-func SaveEvent(date time.Time, event *model.Event) error {
-	// Ensure directories exist
-	dirPath := GetDayDirPath(date)
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return fmt.Errorf("failed to create day directory: %w", err)
-	}
-	
-	// Generate filename
-	filename := event.GenerateFilename()
-	filePath := filepath.Join(dirPath, filename)
-	
-	// Check for duplicate filename (same time and title)
-	if _, err := os.Stat(filePath); err == nil {
-		// File exists, add a suffix
-		for i := 2; i < 100; i++ {
-			altFilePath := filepath.Join(dirPath, fmt.Sprintf("%s_%d", filename, i))
-			if _, err := os.Stat(altFilePath); os.IsNotExist(err) {
-				filePath = altFilePath
-				break
-			}
-		}
-	}
-	
-	// Write event content
-	content := event.FormatFileContent()
-	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write event file: %w", err)
+// keepExtra carries header keys bubblecal doesn't know over from the
+// stored copy of an event to its replacement. Set newEvent.Extra to an
+// empty, non-nil slice to drop them.
+func keepExtra(stored, newEvent *model.Event) {
+	if newEvent.Extra == nil {
+		newEvent.Extra = append([]model.Property(nil), stored.Extra...)
 	}
-	
-	return nil
 }
 
-// DeleteEvent deletes a single event file
-func DeleteEvent(date time.Time, eventToDelete *model.Event) error {
-	dirPath := GetDayDirPath(date)
-	
-	// Find the matching file
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		return fmt.Errorf("failed to read day directory: %w", err)
-	}
-	
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		
-		filename := entry.Name()
-		filePath := filepath.Join(dirPath, filename)
-		
-		// Read and parse to check if it matches
-		content, err := os.ReadFile(filePath)
-		if err != nil {
-			continue
-		}
-		
-		event, err := model.ParseEventFromFilename(filename, string(content))
-		if err != nil {
-			continue
-		}
-		
-		// Check if this is the event to delete
-		if event.StartTime == eventToDelete.StartTime &&
-		   event.EndTime == eventToDelete.EndTime &&
-		   event.Title == eventToDelete.Title {
-			// Delete the file
-			if err := os.Remove(filePath); err != nil {
-				return fmt.Errorf("failed to delete event file: %w", err)
-			}
-			
-			// Clean up empty directory
-			if entries, _ := os.ReadDir(dirPath); len(entries) == 0 {
-				os.Remove(dirPath)
-			}
-			
-			return nil
-		}
-	}
-	
-	return fmt.Errorf("event not found")
+// sameEvent reports whether two events refer to the same stored event
+func sameEvent(a, b *model.Event) bool {
+	return a.ID != "" && a.ID == b.ID
 }
 
-// UpdateEvent updates an existing event (might need to rename file)
-func UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
-	// First delete the old event
-	if err := DeleteEvent(date, oldEvent); err != nil {
-		return fmt.Errorf("failed to delete old event: %w", err)
-	}
-	
-	// Then save the new event
-	if err := SaveEvent(date, newEvent); err != nil {
-		// Try to restore old event
-		SaveEvent(date, oldEvent)
-		return fmt.Errorf("failed to save updated event: %w", err)
-	}
-	
-	return nil
-}
+// sortEvents sorts the events shown on date by time (all-day events go
+// first, multi-day ones before single-day ones)
+func sortEvents(events []*model.Event, date time.Time) {
+	sort.SliceStable(events, func(i, j int) bool {
+		si, sj := events[i].SegmentOn(date), events[j].SegmentOn(date)
 
-// sortEvents sorts events by time (all-day events go first)
-func sortEvents(events []*model.Event) {
-	sort.Slice(events, func(i, j int) bool {
 		// All-day events go first
-		if events[i].IsAllDay() && events[j].IsAllDay() {
+		if si.AllDay && sj.AllDay {
+			if events[i].IsMultiDay() != events[j].IsMultiDay() {
+				return events[i].IsMultiDay()
+			}
+			if events[i].StartDate != events[j].StartDate {
+				return events[i].StartDate < events[j].StartDate // longer-running spans first
+			}
 			return events[i].Title < events[j].Title // sort all-day by title
 		}
-		if events[i].IsAllDay() {
+		if si.AllDay {
 			return true // i goes before j
 		}
-		if events[j].IsAllDay() {
+		if sj.AllDay {
 			return false // i goes after j
 		}
-		
+
 		// Both are timed events, sort by start time
-		ti, erri := events[i].GetStartTime()
-		tj, errj := events[j].GetStartTime()
-		
+		ti, erri := time.Parse("15:04", si.Start)
+		tj, errj := time.Parse("15:04", sj.Start)
+
 		// If parsing fails, treat as end of day
 		if erri != nil {
 			return false
@@ -223,7 +150,7 @@ func sortEvents(events []*model.Event) {
 		if errj != nil {
 			return true
 		}
-		
+
 		return ti.Before(tj)
 	})
-}
\ No newline at end of file
+}
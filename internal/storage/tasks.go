@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"bubblecal/internal/model"
+	"sort"
+	"strings"
+)
+
+// TaskStore reads and writes tasks. Stores that keep tasks implement it
+// next to Store.
+type TaskStore interface {
+	// LoadTasks returns every task, sorted by due date
+	LoadTasks() ([]*model.Task, error)
+	// SaveTask adds a task, or replaces the stored task with the same ID
+	SaveTask(task *model.Task) error
+	// DeleteTask removes a task
+	DeleteTask(task *model.Task) error
+}
+
+var (
+	_ TaskStore = (*FileStore)(nil)
+	_ TaskStore = (*MemoryStore)(nil)
+)
+
+// sortTasks sorts tasks by due date and time
+func sortTasks(tasks []*model.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Due != tasks[j].Due {
+			return tasks[i].Due < tasks[j].Due
+		}
+		return tasks[i].DueTime < tasks[j].DueTime
+	})
+}
+
+// Tasks are stored one per file, named after their ID and title:
+//
+//	<root>/tasks/4f2a9c1e8b7d6a50-Send_report
+func (s *FileStore) tasksDir() string {
+	return filepath.Join(s.root, "tasks")
+}
+
+// LoadTasks reads every task file
+func (s *FileStore) LoadTasks() ([]*model.Task, error) {
+	entries, err := os.ReadDir(s.tasksDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	var tasks []*model.Task
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		content, err := s.readFile(filepath.Join(s.tasksDir(), entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		task, err := model.ParseTask(string(content))
+		if err != nil {
+			continue // skip files we can't parse
+		}
+		if task.ID == "" {
+			task.ID, _, _ = strings.Cut(entry.Name(), "-")
+		}
+		tasks = append(tasks, task)
+	}
+	sortTasks(tasks)
+	return tasks, nil
+}
+
+// SaveTask writes the task's file, renaming it if the title changed
+func (s *FileStore) SaveTask(task *model.Task) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if task.ID == "" {
+		task.ID = model.NewID()
+	}
+	if err := os.MkdirAll(s.tasksDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create tasks directory: %w", err)
+	}
+	old, err := s.taskFile(task.ID)
+	if err != nil {
+		return err
+	}
+
+	// A task saved again with its ID, as when restoring it, leaves the
+	// trash
+	s.dropTrashed(task.ID)
+
+	path := filepath.Join(s.tasksDir(), s.taskFilename(task))
+	if err := s.writeFile(path, []byte(task.FormatFileContent())); err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+	if old != "" && old != path {
+		if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old task file: %w", err)
+		}
+	}
+	return nil
+}
+
+// DeleteTask moves the task's file to the trash
+func (s *FileStore) DeleteTask(task *model.Task) error {
+	return s.deleteTask(task, true)
+}
+
+// deleteTask removes the task's file, keeping it in the trash if trash is
+// set
+func (s *FileStore) deleteTask(task *model.Task, trash bool) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path, err := s.taskFile(task.ID)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return fmt.Errorf("task not found")
+	}
+	if trash {
+		// Keep the task as stored, not as the caller last saw it
+		stored := task
+		if content, err := s.readFile(path); err == nil {
+			if t, err := model.ParseTask(string(content)); err == nil {
+				stored = t
+				stored.ID = task.ID
+			}
+		}
+		if err := s.moveTaskToTrash(path, stored); err != nil {
+			return fmt.Errorf("failed to delete task: %w", err)
+		}
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// taskFile returns the path of the task with the given ID, or "" if
+// there is none
+func (s *FileStore) taskFile(id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+	entries, err := os.ReadDir(s.tasksDir())
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read tasks: %w", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), id+"-") {
+			return filepath.Join(s.tasksDir(), entry.Name()), nil
+		}
+	}
+	return "", nil
+}
+
+// LoadTasks returns copies of the stored tasks
+func (s *MemoryStore) LoadTasks() ([]*model.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := make([]*model.Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		tasks = append(tasks, t.Clone())
+	}
+	sortTasks(tasks)
+	return tasks, nil
+}
+
+// SaveTask stores a copy of task, assigning an ID if needed
+func (s *MemoryStore) SaveTask(task *model.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if task.ID == "" {
+		task.ID = model.NewID()
+	}
+	for i, t := range s.tasks {
+		if t.ID == task.ID {
+			s.tasks[i] = task.Clone()
+			return nil
+		}
+	}
+	s.tasks = append(s.tasks, task.Clone())
+	return nil
+}
+
+// DeleteTask removes the stored copy of task
+func (s *MemoryStore) DeleteTask(task *model.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, t := range s.tasks {
+		if t.ID == task.ID {
+			s.tasks = append(s.tasks[:i], s.tasks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("task not found")
+}
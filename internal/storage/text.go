@@ -0,0 +1,449 @@
+package storage
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TextStore keeps each day's events in one text file, in the format of
+// model.ParseEventText, so they can be grepped and edited by hand:
+//
+//	<root>/2025-08-13.txt   the day's events
+//	<root>/2025-08-13.md    the day's note
+//	<root>/spans.txt        multi-day events, each starting with its dates
+//	<root>/recurring.txt    recurring series and their overrides
+//	<root>/tasks/           tasks, one file each as FileStore keeps them
+type TextStore struct {
+	root  string
+	tasks *FileStore // keeps the tasks directory
+
+	mu   sync.Mutex // held along with the directory lock
+	seen *versions  // the files as they were last read
+}
+
+var (
+	_ Store        = (*TextStore)(nil)
+	_ NoteStore    = (*TextStore)(nil)
+	_ TaskStore    = (*TextStore)(nil)
+	_ DayVersioner = (*TextStore)(nil)
+	_ Scanner      = (*TextStore)(nil)
+)
+
+// NewTextStore creates a store keeping its files in root
+func NewTextStore(root string) *TextStore {
+	return &TextStore{root: root, tasks: NewFileStore(root), seen: newVersions()}
+}
+
+// GetTextDir returns the directory the text store keeps its files in
+func GetTextDir() string {
+	return filepath.Join(GetCalendarDir(), "text")
+}
+
+// Root returns the directory the store keeps its files in
+func (s *TextStore) Root() string {
+	return s.root
+}
+
+func (s *TextStore) dayPath(date time.Time) string {
+	return filepath.Join(s.root, dayKey(date)+".txt")
+}
+
+func (s *TextStore) spansPath() string {
+	return filepath.Join(s.root, "spans.txt")
+}
+
+func (s *TextStore) recurringPath() string {
+	return filepath.Join(s.root, "recurring.txt")
+}
+
+// eventFiles returns the files holding the events shown on date
+func (s *TextStore) eventFiles(date time.Time) []string {
+	return []string{s.dayPath(date), s.spansPath(), s.recurringPath()}
+}
+
+// lock takes the directory lock for a write to paths. It refuses with
+// ErrConflict if another program changed them since they were read. The
+// returned function records the store's own changes and releases the
+// lock.
+func (s *TextStore) lock(paths ...string) (func(), error) {
+	s.mu.Lock()
+	release, err := LockDir(s.root)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if path := s.seen.changed(paths...); path != "" {
+		release()
+		s.mu.Unlock()
+		return nil, fmt.Errorf("another program changed %s since it was read; %w", filepath.Base(path), ErrConflict)
+	}
+	return func() {
+		s.seen.record(paths...)
+		release()
+		s.mu.Unlock()
+	}, nil
+}
+
+// textFile is the parsed contents of one of the store's event files
+type textFile struct {
+	path   string
+	dated  bool // event lines start with their dates
+	events []*model.Event
+}
+
+// load reads the event file at path; a missing file holds no events.
+// Events added by hand get an ID, which is written back.
+func (s *TextStore) load(path string, dated bool) (*textFile, error) {
+	f := &textFile{path: path, dated: dated}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	f.events, err = model.ParseEventText(string(data), dated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	missing := false
+	for _, event := range f.events {
+		if event.ID == "" {
+			event.ID = model.NewID()
+			missing = true
+		}
+	}
+	if missing {
+		if err := f.save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to assign ids in %s: %v\n", filepath.Base(path), err)
+		}
+	}
+	return f, nil
+}
+
+// save writes the file back, removing it once it holds no events
+func (f *textFile) save() error {
+	if len(f.events) == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove %s: %w", filepath.Base(f.path), err)
+		}
+		return nil
+	}
+	if f.dated {
+		sort.SliceStable(f.events, func(i, j int) bool {
+			return f.events[i].StartDate < f.events[j].StartDate
+		})
+	} else if date, err := time.ParseInLocation(model.DateFormat, strings.TrimSuffix(filepath.Base(f.path), ".txt"), time.Local); err == nil {
+		sortEvents(f.events, date)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return fmt.Errorf("failed to create calendar directory: %w", err)
+	}
+	if err := writeFileAtomic(f.path, []byte(model.FormatEventText(f.events, f.dated)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(f.path), err)
+	}
+	return nil
+}
+
+// fileFor returns the file an event stored on date belongs in
+func (s *TextStore) fileFor(date time.Time, event *model.Event) (string, bool) {
+	if event.InSeries() {
+		return s.recurringPath(), true
+	}
+	if event.IsMultiDay() {
+		return s.spansPath(), true
+	}
+	return s.dayPath(date), false
+}
+
+// find returns the file holding the stored copy of event and its index
+func (s *TextStore) find(date time.Time, event *model.Event) (*textFile, int, error) {
+	for _, where := range []struct {
+		path  string
+		dated bool
+	}{{s.dayPath(date), false}, {s.spansPath(), true}, {s.recurringPath(), true}} {
+		f, err := s.load(where.path, where.dated)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i, evt := range f.events {
+			if sameEvent(evt, event) {
+				return f, i, nil
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("event not found")
+}
+
+// DayVersion stamps the files the events and note of date are read from
+func (s *TextStore) DayVersion(date time.Time) string {
+	version := ""
+	for _, path := range append(s.eventFiles(date), s.notePath(date)) {
+		if info, err := os.Stat(path); err == nil {
+			version += fmt.Sprintf("%d:%d/", info.Size(), info.ModTime().UnixNano())
+		} else {
+			version += "-/"
+		}
+	}
+	return version
+}
+
+// LoadDayEvents returns the events of a day's file plus the multi-day
+// events and occurrences of recurring series covering the date. Overnight
+// events written into the day's file by hand move to spans.txt.
+func (s *TextStore) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	// Reading may move events or give them IDs, so it locks too
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	var events []*model.Event
+	err = s.seen.read(s.eventFiles(date), func() error {
+		var err error
+		events, err = s.loadDay(date)
+		return err
+	})
+	return events, err
+}
+
+// loadDay reads the events shown on date; the caller holds the lock
+func (s *TextStore) loadDay(date time.Time) ([]*model.Event, error) {
+	day, err := s.load(s.dayPath(date), false)
+	if err != nil {
+		return nil, err
+	}
+	spans, err := s.load(s.spansPath(), true)
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.load(s.recurringPath(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := day.events[:0]
+	moved := false
+	for _, event := range day.events {
+		if event.IsOvernight() {
+			event.SpanOvernight(date)
+			spans.events = append(spans.events, event)
+			moved = true
+			continue
+		}
+		kept = append(kept, event)
+	}
+	if moved {
+		day.events = kept
+		if err := spans.save(); err != nil {
+			return nil, err
+		}
+		if err := day.save(); err != nil {
+			return nil, err
+		}
+	}
+
+	events := append([]*model.Event(nil), day.events...)
+	for _, span := range spans.events {
+		if span.Covers(date) {
+			events = append(events, span)
+		}
+	}
+	events = append(events, expandSeries(series.events, date)...)
+	sortEvents(events, date)
+	return events, nil
+}
+
+// LoadRange loads the events of every day between from and to
+func (s *TextStore) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	return loadRange(from, to, s.LoadDayEvents)
+}
+
+// SaveEvent adds event to the file it belongs in, assigning an ID if
+// needed
+func (s *TextStore) SaveEvent(date time.Time, event *model.Event) error {
+	unlock, err := s.lock(s.eventFiles(date)...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if event.ID == "" {
+		event.ID = model.NewID()
+	}
+	prepareEvent(date, event)
+	path, dated := s.fileFor(date, event)
+	f, err := s.load(path, dated)
+	if err != nil {
+		return err
+	}
+	f.events = append(f.events, event.Clone())
+	return f.save()
+}
+
+// UpdateEvent replaces the event with oldEvent's ID by newEvent, moving
+// it to another file if needed. The new copy is written before the old
+// one is removed.
+func (s *TextStore) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	return s.MoveEvent(date, date, oldEvent, newEvent)
+}
+
+// MoveEvent replaces the event with oldEvent's ID on from by newEvent on
+// to, writing the new copy before removing the old one
+func (s *TextStore) MoveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error {
+	unlock, err := s.lock(append(s.eventFiles(from), s.dayPath(to))...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	src, i, err := s.find(from, oldEvent)
+	if err != nil {
+		return err
+	}
+	stored := src.events[i]
+	newEvent.ID = oldEvent.ID
+	prepareEvent(to, newEvent)
+	rebaseSeries(stored, oldEvent, newEvent)
+	keepExtra(stored, newEvent)
+
+	path, dated := s.fileFor(to, newEvent)
+	if path == src.path {
+		src.events[i] = newEvent.Clone()
+		return src.save()
+	}
+	dst, err := s.load(path, dated)
+	if err != nil {
+		return err
+	}
+	dst.events = append(dst.events, newEvent.Clone())
+	if err := dst.save(); err != nil {
+		return err
+	}
+	src.events = append(src.events[:i], src.events[i+1:]...)
+	return src.save()
+}
+
+// DeleteEvent removes the event with the same ID
+func (s *TextStore) DeleteEvent(date time.Time, event *model.Event) error {
+	unlock, err := s.lock(s.eventFiles(date)...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, i, err := s.find(date, event)
+	if err != nil {
+		return err
+	}
+	f.events = append(f.events[:i], f.events[i+1:]...)
+	return f.save()
+}
+
+// LoadSeries returns a recurring series and its overrides
+func (s *TextStore) LoadSeries(id string) (*model.Event, []*model.Event, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	var f *textFile
+	err = s.seen.read([]string{s.recurringPath()}, func() error {
+		var err error
+		f, err = s.load(s.recurringPath(), true)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return findSeries(f.events, id)
+}
+
+func (s *TextStore) notePath(date time.Time) string {
+	return filepath.Join(s.root, dayKey(date)+".md")
+}
+
+// LoadNote reads the day's note file
+func (s *TextStore) LoadNote(date time.Time) (string, error) {
+	var data []byte
+	err := s.seen.read([]string{s.notePath(date)}, func() error {
+		var err error
+		data, err = os.ReadFile(s.notePath(date))
+		return err
+	})
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read note: %w", err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// SaveNote writes the day's note file, removing it when the note is empty
+func (s *TextStore) SaveNote(date time.Time, note string) error {
+	unlock, err := s.lock(s.notePath(date))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	note = strings.TrimRight(note, "\n\r\t ")
+	if note == "" {
+		if err := os.Remove(s.notePath(date)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete note: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(s.root, 0755); err != nil {
+		return fmt.Errorf("failed to create calendar directory: %w", err)
+	}
+	if err := writeFileAtomic(s.notePath(date), []byte(note+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+	return nil
+}
+
+// LoadTasks reads the tasks directory
+func (s *TextStore) LoadTasks() ([]*model.Task, error) {
+	return s.tasks.LoadTasks()
+}
+
+// SaveTask writes a task's file
+func (s *TextStore) SaveTask(task *model.Task) error {
+	return s.tasks.SaveTask(task)
+}
+
+// DeleteTask removes a task's file; the text format has no trash
+func (s *TextStore) DeleteTask(task *model.Task) error {
+	return s.tasks.deleteTask(task, false)
+}
+
+// Scan stamps the text and note files of the days from from to to,
+// spans.txt, recurring.txt and the tasks directory
+func (s *TextStore) Scan(from, to time.Time) (map[string]string, error) {
+	stamps := make(map[string]string)
+	stamp := func(key, name string) {
+		if info, err := os.Stat(filepath.Join(s.root, name)); err == nil && !info.IsDir() {
+			stamps[key] += fmt.Sprintf("%s:%d:%d/", name, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	for _, key := range dayKeys(from, to) {
+		stamp("days/"+key, key+".txt")
+		stamp("days/"+key, key+".md")
+	}
+	stamp("spans", "spans.txt")
+	stamp("recurring", "recurring.txt")
+	if stamp := stampDir(s.tasks.tasksDir()); stamp != "" {
+		stamps["tasks"] = stamp
+	}
+	return stamps, nil
+}
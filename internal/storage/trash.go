@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrashStore keeps deleted events and tasks for a while so they can be
+// restored. Stores with a trash implement it next to Store. Saving a
+// trashed event on its date, or a trashed task, again restores it and
+// takes it out of the trash.
+type TrashStore interface {
+	// LoadTrash returns the deleted events, most recently deleted first
+	LoadTrash() ([]*TrashedEvent, error)
+	// PurgeTrashed deletes a deleted event for good
+	PurgeTrashed(item *TrashedEvent) error
+	// PurgeTrashBefore deletes for good what was deleted before cutoff
+	// and returns how many events that was
+	PurgeTrashBefore(cutoff time.Time) (int, error)
+}
+
+// TrashedEvent is an event, or a task, waiting in the trash
+type TrashedEvent struct {
+	Event   *model.Event
+	Task    *model.Task // set instead of Event for a deleted task
+	Date    time.Time   // the date the event was deleted from
+	Deleted time.Time
+	name    string // file in the trash directory
+}
+
+// Title returns the title of the deleted event or task
+func (t *TrashedEvent) Title() string {
+	if t.Task != nil {
+		return t.Task.Title
+	}
+	return t.Event.Title
+}
+
+// Discarder is implemented by stores with a trash, and the stores
+// wrapping them, to delete an event without keeping it in the trash, as
+// when undoing its creation
+type Discarder interface {
+	DiscardEvent(date time.Time, event *model.Event) error
+}
+
+var (
+	_ TrashStore = (*FileStore)(nil)
+	_ Discarder  = (*FileStore)(nil)
+	_ Discarder  = (*CachedStore)(nil)
+	_ Discarder  = (*GitStore)(nil)
+)
+
+// discardEvent deletes event from store, keeping it out of the trash if
+// the store has one
+func discardEvent(store Store, date time.Time, event *model.Event) error {
+	if d, ok := store.(Discarder); ok {
+		return d.DiscardEvent(date, event)
+	}
+	return store.DeleteEvent(date, event)
+}
+
+// Deleted event files are moved to the trash directory, one file each,
+// named after when they were deleted and their ID:
+//
+//	<root>/trash/1760621400000000000-4f2a9c1e8b7d6a50
+//
+// The file starts with where the event came from, then a blank line and
+// the event file as it was (unsealed, in an encrypted calendar, where the
+// whole entry is sealed instead):
+//
+//	date:2025-08-13
+//	deleted:2025-10-16T15:30:00+02:00
+//	file:days/2025-08-13/0900-1000-Team_Standup
+//
+// Deleted tasks are kept the same way, without a date and with a file
+// under tasks/.
+//
+//	id:4f2a9c1e8b7d6a50
+func (s *FileStore) trashDir() string {
+	return filepath.Join(s.root, "trash")
+}
+
+// moveToTrash replaces the event file at filePath, deleted from date, by
+// an entry in the trash
+func (s *FileStore) moveToTrash(date time.Time, filePath, id string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(s.root, filePath)
+	if err != nil {
+		return err
+	}
+	if s.cipher != nil {
+		// Keep the event unsealed under its plain name, which obscured
+		// names don't give
+		event, err := s.decodeEvent(rel, content)
+		if err != nil {
+			return err
+		}
+		rel = filepath.Join(filepath.Dir(rel), storedFilename(event))
+		content = []byte(event.FormatFileContent())
+	}
+	if err := os.MkdirAll(s.trashDir(), 0755); err != nil {
+		return err
+	}
+	now := time.Now()
+	header := fmt.Sprintf("date:%s\ndeleted:%s\nfile:%s\n\n", dayKey(date), now.Format(time.RFC3339), filepath.ToSlash(rel))
+	name := fmt.Sprintf("%d-%s", now.UnixNano(), id)
+	if err := s.writeFile(filepath.Join(s.trashDir(), name), append([]byte(header), content...)); err != nil {
+		return err
+	}
+	return os.Remove(filePath)
+}
+
+// moveTaskToTrash replaces the task file at path by an entry in the
+// trash
+func (s *FileStore) moveTaskToTrash(path string, task *model.Task) error {
+	if err := os.MkdirAll(s.trashDir(), 0755); err != nil {
+		return err
+	}
+	now := time.Now()
+	rel := filepath.Join("tasks", task.Filename())
+	header := fmt.Sprintf("deleted:%s\nfile:%s\n\n", now.Format(time.RFC3339), filepath.ToSlash(rel))
+	name := fmt.Sprintf("%d-%s", now.UnixNano(), task.ID)
+	if err := s.writeFile(filepath.Join(s.trashDir(), name), []byte(header+task.FormatFileContent())); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// LoadTrash reads the trash directory, skipping entries it can't parse
+func (s *FileStore) LoadTrash() ([]*TrashedEvent, error) {
+	entries, err := os.ReadDir(s.trashDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var items []*TrashedEvent
+	for _, entry := range entries {
+		if entry.IsDir() || isHiddenName(entry.Name()) {
+			continue
+		}
+		item, err := s.readTrashed(entry.Name())
+		if err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Deleted.After(items[j].Deleted)
+	})
+	return items, nil
+}
+
+// readTrashed parses the trash entry name
+func (s *FileStore) readTrashed(name string) (*TrashedEvent, error) {
+	data, err := s.readFile(filepath.Join(s.trashDir(), name))
+	if err != nil {
+		return nil, err
+	}
+	header, content, _ := strings.Cut(string(data), "\n\n")
+	item := &TrashedEvent{name: name}
+	file := ""
+	for _, line := range strings.Split(header, "\n") {
+		key, value, _ := strings.Cut(line, ":")
+		switch key {
+		case "date":
+			item.Date, err = time.ParseInLocation(model.DateFormat, value, time.Local)
+		case "deleted":
+			item.Deleted, err = time.Parse(time.RFC3339, value)
+		case "file":
+			file = filepath.FromSlash(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid trash entry %s: %w", name, err)
+		}
+	}
+	if filepath.Dir(file) == "tasks" {
+		if item.Task, err = model.ParseTask(content); err != nil {
+			return nil, fmt.Errorf("invalid trash entry %s: %w", name, err)
+		}
+		return item, nil
+	}
+	if file == "" || item.Date.IsZero() {
+		return nil, fmt.Errorf("invalid trash entry %s", name)
+	}
+
+	item.Event, err = parseStoredEvent(file, content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trash entry %s: %w", name, err)
+	}
+	if item.Event.ID == "" {
+		_, item.Event.ID, _ = strings.Cut(name, "-")
+	}
+	return item, nil
+}
+
+// PurgeTrashed removes the trash entry
+func (s *FileStore) PurgeTrashed(item *TrashedEvent) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := os.Remove(filepath.Join(s.trashDir(), item.name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to purge event: %w", err)
+	}
+	return nil
+}
+
+// PurgeTrashBefore removes the entries deleted before cutoff. Entry names
+// start with the deletion time, so they aren't opened.
+func (s *FileStore) PurgeTrashBefore(cutoff time.Time) (int, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	entries, err := os.ReadDir(s.trashDir())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read trash: %w", err)
+	}
+	purged := 0
+	for _, entry := range entries {
+		stamp, _, _ := strings.Cut(entry.Name(), "-")
+		nanos, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil || !time.Unix(0, nanos).Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.trashDir(), entry.Name())); err != nil {
+			return purged, fmt.Errorf("failed to purge event: %w", err)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// dropTrashed removes the trash entries of the event or task with id,
+// which has been saved again
+func (s *FileStore) dropTrashed(id string) {
+	matches, _ := filepath.Glob(filepath.Join(s.trashDir(), "*-"+id))
+	for _, path := range matches {
+		os.Remove(path)
+	}
+}
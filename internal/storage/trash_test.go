@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"testing"
+)
+
+func TestDeletedTaskWaitsInTrash(t *testing.T) {
+	store := NewFileStore(t.TempDir())
+	task := &model.Task{Title: "Send report", Due: "2025-08-20", Priority: model.PriorityHigh}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteTask(task); err != nil {
+		t.Fatal(err)
+	}
+	if tasks, _ := store.LoadTasks(); len(tasks) != 0 {
+		t.Fatalf("got %d tasks after deleting the only one", len(tasks))
+	}
+
+	trash, err := store.LoadTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash) != 1 || trash[0].Task == nil || trash[0].Title() != "Send report" {
+		t.Fatalf("got %v in the trash, want the task", trash)
+	}
+
+	// Saving it again restores it and empties the trash
+	if err := store.SaveTask(trash[0].Task); err != nil {
+		t.Fatal(err)
+	}
+	tasks, _ := store.LoadTasks()
+	if len(tasks) != 1 || tasks[0].ID != task.ID || tasks[0].FormatFileContent() != task.FormatFileContent() {
+		t.Errorf("restored %v, want %v", tasks, task)
+	}
+	if trash, _ := store.LoadTrash(); len(trash) != 0 {
+		t.Errorf("%d entries left in the trash after restoring", len(trash))
+	}
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"bubblecal/internal/model"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxUndo bounds how many changes can be undone
+const maxUndo = 100
+
+// UndoLog records the writes made through it so they can be undone and
+// redone. Writes are collected into one change until Seal is called, so
+// an edit that takes several writes, like changing one occurrence of a
+// series, is undone in one step. The log is kept in a file, if given one,
+// so it survives restarts.
+type UndoLog struct {
+	store Store
+	path  string // "" to keep the log in memory only
+
+	mu   sync.Mutex
+	open []undoOp   // writes since the last Seal
+	undo [][]undoOp // changes that can be undone, oldest first
+	redo [][]undoOp // changes undone since the last write, oldest first
+}
+
+// undoOp is one recorded write. Before is nil for saves and After is nil
+// for deletes. To is set when an update moved the event to another day.
+type undoOp struct {
+	Date   string       `json:"date"`
+	To     string       `json:"to,omitempty"`
+	Before *model.Event `json:"before,omitempty"`
+	After  *model.Event `json:"after,omitempty"`
+}
+
+// reversed returns the write that undoes op
+func (op undoOp) reversed() undoOp {
+	if op.To != "" {
+		return undoOp{Date: op.To, To: op.Date, Before: op.After, After: op.Before}
+	}
+	return undoOp{Date: op.Date, Before: op.After, After: op.Before}
+}
+
+var _ Store = (*UndoLog)(nil)
+
+// NewUndoLog wraps store, keeping the log in the file at path. A log
+// that can't be read is started afresh.
+func NewUndoLog(store Store, path string) *UndoLog {
+	l := &UndoLog{store: store, path: path}
+	if path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			var saved struct {
+				Undo [][]undoOp `json:"undo"`
+				Redo [][]undoOp `json:"redo"`
+			}
+			if json.Unmarshal(data, &saved) == nil {
+				l.undo, l.redo = saved.Undo, saved.Redo
+			}
+		}
+	}
+	return l
+}
+
+// LoadDayEvents reads from the wrapped store
+func (l *UndoLog) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	return l.store.LoadDayEvents(date)
+}
+
+// LoadRange reads from the wrapped store
+func (l *UndoLog) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	return l.store.LoadRange(from, to)
+}
+
+// LoadSeries reads from the wrapped store
+func (l *UndoLog) LoadSeries(id string) (*model.Event, []*model.Event, error) {
+	return l.store.LoadSeries(id)
+}
+
+// SaveEvent saves event and records it
+func (l *UndoLog) SaveEvent(date time.Time, event *model.Event) error {
+	if err := l.store.SaveEvent(date, event); err != nil {
+		return err
+	}
+	l.record(undoOp{Date: dayKey(date), After: event.Clone()})
+	return nil
+}
+
+// UpdateEvent updates an event and records the change
+func (l *UndoLog) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	before := oldEvent.Clone()
+	if err := l.store.UpdateEvent(date, oldEvent, newEvent); err != nil {
+		return err
+	}
+	l.record(undoOp{Date: dayKey(date), Before: before, After: newEvent.Clone()})
+	return nil
+}
+
+// MoveEvent moves an event to another day and records the change
+func (l *UndoLog) MoveEvent(from, to time.Time, oldEvent, newEvent *model.Event) error {
+	before := oldEvent.Clone()
+	if err := moveEvent(l.store, from, to, oldEvent, newEvent); err != nil {
+		return err
+	}
+	op := undoOp{Date: dayKey(from), Before: before, After: newEvent.Clone()}
+	if dayKey(to) != op.Date {
+		op.To = dayKey(to)
+	}
+	l.record(op)
+	return nil
+}
+
+// DeleteEvent deletes an event and records it
+func (l *UndoLog) DeleteEvent(date time.Time, event *model.Event) error {
+	before := event.Clone()
+	if err := l.store.DeleteEvent(date, event); err != nil {
+		return err
+	}
+	l.record(undoOp{Date: dayKey(date), Before: before})
+	return nil
+}
+
+func (l *UndoLog) record(op undoOp) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.open = append(l.open, op)
+}
+
+// Seal ends the change the writes since the last Seal belong to. A new
+// change can't be redone past, so it clears what was undone.
+func (l *UndoLog) Seal() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.open) == 0 {
+		return nil
+	}
+	l.undo = append(l.undo, l.open)
+	if len(l.undo) > maxUndo {
+		l.undo = l.undo[len(l.undo)-maxUndo:]
+	}
+	l.open = nil
+	l.redo = nil
+	return l.save()
+}
+
+// Undo reverts the last change and describes it, or returns "" if there
+// is nothing to undo
+func (l *UndoLog) Undo() (string, error) {
+	l.Seal()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.undo) == 0 {
+		return "", nil
+	}
+	change := l.undo[len(l.undo)-1]
+	steps := make([]undoOp, 0, len(change))
+	for i := len(change) - 1; i >= 0; i-- {
+		steps = append(steps, change[i].reversed())
+	}
+	l.undo = l.undo[:len(l.undo)-1]
+	if err := l.applyAll(steps, false); err != nil {
+		if errors.Is(err, errPartial) {
+			l.save()
+		} else {
+			l.undo = append(l.undo, change)
+		}
+		return "", fmt.Errorf("failed to undo: %w", err)
+	}
+	l.redo = append(l.redo, change)
+	return describeChange(change), l.save()
+}
+
+// Redo repeats the last undone change and describes it, or returns "" if
+// there is nothing to redo
+func (l *UndoLog) Redo() (string, error) {
+	l.Seal()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.redo) == 0 {
+		return "", nil
+	}
+	change := l.redo[len(l.redo)-1]
+	l.redo = l.redo[:len(l.redo)-1]
+	if err := l.applyAll(change, true); err != nil {
+		if errors.Is(err, errPartial) {
+			l.save()
+		} else {
+			l.redo = append(l.redo, change)
+		}
+		return "", fmt.Errorf("failed to redo: %w", err)
+	}
+	l.undo = append(l.undo, change)
+	return describeChange(change), l.save()
+}
+
+// errPartial is returned by applyAll for a change left partly applied
+var errPartial = errors.New("the change was only partly made and can't be tried again")
+
+// applyAll applies each op of a change in order, moving deleted events
+// to the trash if trash is set. If one fails, the ones before it are
+// reverted, so the change can be tried again; if that fails too, the
+// error wraps errPartial.
+func (l *UndoLog) applyAll(ops []undoOp, trash bool) error {
+	for i, op := range ops {
+		err := l.apply(op, trash)
+		if err == nil {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if l.apply(ops[j].reversed(), false) != nil {
+				return fmt.Errorf("%w: %w", err, errPartial)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// apply turns the event stored as op.Before into op.After, where a nil
+// event means none. It writes to the wrapped store, so nothing is
+// recorded. Deleted events go to the trash only if trash is set: redoing
+// a deletion keeps it like the deletion did, while undoing an addition
+// leaves nothing.
+func (l *UndoLog) apply(op undoOp, trash bool) error {
+	day, err := time.ParseInLocation(model.DateFormat, op.Date, time.Local)
+	if err != nil {
+		return err
+	}
+	from, to := op.Before, op.After
+	switch {
+	case from == nil:
+		return l.store.SaveEvent(day, to.Clone())
+	case to == nil && trash:
+		return l.store.DeleteEvent(day, from.Clone())
+	case to == nil:
+		return discardEvent(l.store, day, from.Clone())
+	case op.To != "":
+		toDay, err := time.ParseInLocation(model.DateFormat, op.To, time.Local)
+		if err != nil {
+			return err
+		}
+		return moveEvent(l.store, day, toDay, from.Clone(), to.Clone())
+	default:
+		return l.store.UpdateEvent(day, from.Clone(), to.Clone())
+	}
+}
+
+// save writes the log to its file; callers hold l.mu
+func (l *UndoLog) save() error {
+	if l.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(struct {
+		Undo [][]undoOp `json:"undo"`
+		Redo [][]undoOp `json:"redo"`
+	}{l.undo, l.redo})
+	if err != nil {
+		return fmt.Errorf("failed to save undo log: %w", err)
+	}
+	if err := writeFileAtomic(l.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save undo log: %w", err)
+	}
+	return nil
+}
+
+// describeChange names a change for status messages, such as
+// "deleting Team Standup" or "3 changes to Daily Standup"
+func describeChange(change []undoOp) string {
+	op := change[len(change)-1]
+	title := ""
+	if op.After != nil {
+		title = op.After.Title
+	} else {
+		title = op.Before.Title
+	}
+	if len(change) > 1 {
+		return fmt.Sprintf("%d changes to %s", len(change), title)
+	}
+	switch {
+	case op.Before == nil:
+		return "adding " + title
+	case op.After == nil:
+		return "deleting " + title
+	}
+	return "editing " + title
+}
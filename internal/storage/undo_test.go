@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingStore fails the writes to the store it wraps that fail picks,
+// counting from 1
+type failingStore struct {
+	Store
+	fail   func(n int) bool
+	writes int
+}
+
+var errDiskFull = errors.New("disk full")
+
+func (s *failingStore) write() error {
+	s.writes++
+	if s.fail(s.writes) {
+		return errDiskFull
+	}
+	return nil
+}
+
+func (s *failingStore) SaveEvent(date time.Time, event *model.Event) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.Store.SaveEvent(date, event)
+}
+
+func (s *failingStore) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.Store.UpdateEvent(date, oldEvent, newEvent)
+}
+
+func (s *failingStore) DeleteEvent(date time.Time, event *model.Event) error {
+	if err := s.write(); err != nil {
+		return err
+	}
+	return s.Store.DeleteEvent(date, event)
+}
+
+// twoOpChange saves one event and edits another in one change, and
+// returns the events' titles as they are afterwards
+func twoOpChange(t *testing.T, log *UndoLog, store Store) string {
+	t.Helper()
+	kept := &model.Event{StartTime: "09:00", Title: "Kept"}
+	if err := store.SaveEvent(testDate, kept); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.SaveEvent(testDate, &model.Event{StartTime: "10:00", Title: "Added"}); err != nil {
+		t.Fatal(err)
+	}
+	edited := kept.Clone()
+	edited.Title = "Edited"
+	if err := log.UpdateEvent(testDate, kept, edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := log.Seal(); err != nil {
+		t.Fatal(err)
+	}
+	return dayTitles(t, store)
+}
+
+func dayTitles(t *testing.T, store Store) string {
+	t.Helper()
+	events, err := store.LoadDayEvents(testDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ""
+	for _, e := range events {
+		s += e.Title + ";"
+	}
+	return s
+}
+
+func TestUndoRollsBackPartialChange(t *testing.T) {
+	store := NewMemoryStore()
+	log := NewUndoLog(store, "")
+	after := twoOpChange(t, log, store)
+
+	// Undoing the edit works, removing the added event doesn't
+	log.store = &failingStore{Store: store, fail: func(n int) bool { return n == 2 }}
+	if _, err := log.Undo(); !errors.Is(err, errDiskFull) || errors.Is(err, errPartial) {
+		t.Fatalf("undo: %v, want it to fail and roll back", err)
+	}
+	if got := dayTitles(t, store); got != after {
+		t.Errorf("after a failed undo: %q, want the change still made: %q", got, after)
+	}
+
+	// The change is still there to undo, exactly once
+	log.store = store
+	if what, err := log.Undo(); err != nil || what == "" {
+		t.Fatalf("undo: %q, %v", what, err)
+	}
+	if got := dayTitles(t, store); got != "Kept;" {
+		t.Errorf("after undoing: %q, want Kept;", got)
+	}
+	if what, _ := log.Undo(); what != "" {
+		t.Errorf("undid %q as well", what)
+	}
+
+	// The same goes for redoing
+	log.store = &failingStore{Store: store, fail: func(n int) bool { return n == 2 }}
+	if _, err := log.Redo(); !errors.Is(err, errDiskFull) || errors.Is(err, errPartial) {
+		t.Fatalf("redo: %v, want it to fail and roll back", err)
+	}
+	if got := dayTitles(t, store); got != "Kept;" {
+		t.Errorf("after a failed redo: %q, want Kept;", got)
+	}
+	log.store = store
+	if _, err := log.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if got := dayTitles(t, store); got != after {
+		t.Errorf("after redoing: %q, want %q", got, after)
+	}
+}
+
+func TestUndoDropsChangeThatCantBeRolledBack(t *testing.T) {
+	store := NewMemoryStore()
+	log := NewUndoLog(store, "")
+	twoOpChange(t, log, store)
+
+	// Putting the edit back fails too
+	log.store = &failingStore{Store: store, fail: func(n int) bool { return n > 1 }}
+	if _, err := log.Undo(); !errors.Is(err, errPartial) {
+		t.Fatalf("undo: %v, want errPartial", err)
+	}
+	log.store = store
+	if what, err := log.Undo(); what != "" || err != nil {
+		t.Errorf("the partly undone change can still be undone: %q, %v", what, err)
+	}
+	if what, err := log.Redo(); what != "" || err != nil {
+		t.Errorf("the partly undone change can be redone: %q, %v", what, err)
+	}
+}
+
+func TestUndoRedoKeepsTrashEmpty(t *testing.T) {
+	files := NewFileStore(t.TempDir())
+	log := NewUndoLog(NewCachedStore(files), "")
+	if err := log.SaveEvent(testDate, &model.Event{StartTime: "09:00", Title: "Added"}); err != nil {
+		t.Fatal(err)
+	}
+	log.Seal()
+	for i := 0; i < 3; i++ {
+		if _, err := log.Undo(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := log.Redo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := log.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if trash, _ := files.LoadTrash(); len(trash) != 0 {
+		t.Errorf("undoing an addition left %d events in the trash", len(trash))
+	}
+
+	// Redoing a deletion keeps the event in the trash like the deletion
+	log.Redo()
+	events, _ := files.LoadDayEvents(testDate)
+	if err := log.DeleteEvent(testDate, events[0]); err != nil {
+		t.Fatal(err)
+	}
+	log.Seal()
+	log.Undo()
+	log.Redo()
+	if trash, _ := files.LoadTrash(); len(trash) != 1 {
+		t.Errorf("got %d events in the trash after redoing a deletion, want 1", len(trash))
+	}
+}
+
+func TestUndoMoveToAnotherDay(t *testing.T) {
+	files := NewFileStore(t.TempDir())
+	log := NewUndoLog(files, "")
+	nextDay := testDate.AddDate(0, 0, 1)
+	event := &model.Event{StartTime: "23:00", EndTime: "23:30", Title: "Call"}
+	if err := files.SaveEvent(testDate, event); err != nil {
+		t.Fatal(err)
+	}
+	moved := event.Clone()
+	moved.StartTime, moved.EndTime = "01:00", "01:30"
+	if err := log.MoveEvent(testDate, nextDay, event, moved); err != nil {
+		t.Fatal(err)
+	}
+	log.Seal()
+
+	on := func(date time.Time) string {
+		events, _ := files.LoadDayEvents(date)
+		var times []string
+		for _, e := range events {
+			times = append(times, e.StartTime)
+		}
+		return strings.Join(times, " ")
+	}
+	if _, err := log.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if on(testDate) != "23:00" || on(nextDay) != "" {
+		t.Errorf("after undo: %q on the 13th, %q on the 14th", on(testDate), on(nextDay))
+	}
+	if _, err := log.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if on(testDate) != "" || on(nextDay) != "01:00" {
+		t.Errorf("after redo: %q on the 13th, %q on the 14th", on(testDate), on(nextDay))
+	}
+	if trash, _ := files.LoadTrash(); len(trash) != 0 {
+		t.Errorf("moving the event left %d events in the trash", len(trash))
+	}
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Scanner is implemented by stores whose data other programs can change,
+// such as a directory that scripts write event files into. Comparing two
+// scans tells what changed in between.
+type Scanner interface {
+	// Scan returns a stamp for every day from from to to and for
+	// everything shared by many days, keyed as described for Changes.
+	// Days outside the range aren't looked at.
+	Scan(from, to time.Time) (map[string]string, error)
+}
+
+var _ Scanner = (*FileStore)(nil)
+
+// Changes is the difference between two scans
+type Changes struct {
+	Days   []string // "2006-01-02" keys of the days whose files changed
+	Shared bool     // multi-day or recurring events, or tasks, changed
+}
+
+// Empty reports whether nothing changed
+func (c Changes) Empty() bool {
+	return len(c.Days) == 0 && !c.Shared
+}
+
+// Compare returns what changed from the scan before to the scan after.
+// Keys starting with "days/" are days; every other key is shared.
+func Compare(before, after map[string]string) Changes {
+	var c Changes
+	note := func(key string) {
+		if day, ok := strings.CutPrefix(key, "days/"); ok {
+			c.Days = append(c.Days, day)
+		} else {
+			c.Shared = true
+		}
+	}
+	for key, stamp := range after {
+		if before[key] != stamp {
+			note(key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			note(key)
+		}
+	}
+	return c
+}
+
+// Scan stamps the directories of the days from from to to and the spans,
+// recurring and tasks directories with the names, sizes and modification
+// times of their files, so files edited in place are noticed along with
+// added, removed and renamed ones.
+func (s *FileStore) Scan(from, to time.Time) (map[string]string, error) {
+	stamps := make(map[string]string)
+	for _, key := range dayKeys(from, to) {
+		if stamp := stampDir(filepath.Join(s.daysDir(), key)); stamp != "" {
+			stamps["days/"+key] = stamp
+		}
+	}
+	for _, dir := range []string{s.spansDir(), s.recurringDir(), s.tasksDir()} {
+		if stamp := stampDir(dir); stamp != "" {
+			stamps[filepath.Base(dir)] = stamp
+		}
+	}
+	return stamps, nil
+}
+
+// dayKeys returns the "2006-01-02" keys of the days from from to to
+func dayKeys(from, to time.Time) []string {
+	var keys []string
+	if from.IsZero() || to.IsZero() {
+		return nil
+	}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		keys = append(keys, date.Format(model.DateFormat))
+	}
+	return keys
+}
+
+// stampDir describes the files in dir, or returns "" if it can't be read
+func stampDir(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d/", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"time"
+)
+
+// ZonedStore shows the events of another store in a display time zone.
+// Events with a TimeZone are converted on load, which can move them to a
+// neighbouring day; floating events are passed through as they are.
+//
+// Converted events have DisplayZone set and are converted back before
+// they are written, so they can be handed straight back to SaveEvent,
+// UpdateEvent or DeleteEvent. Events in their own zone (DisplayZone
+// empty), like the ones LoadSeries returns, are written unchanged.
+type ZonedStore struct {
+	store Store
+	loc   *time.Location
+}
+
+var _ Store = (*ZonedStore)(nil)
+
+// NewZonedStore wraps store to display its events in loc
+func NewZonedStore(store Store, loc *time.Location) *ZonedStore {
+	return &ZonedStore{store: store, loc: loc}
+}
+
+// Location returns the display time zone
+func (z *ZonedStore) Location() *time.Location {
+	return z.loc
+}
+
+// LoadDayEvents returns the events shown on date in the display zone.
+// UTC offsets run from -12 to +14 hours, so two zones differ by up to
+// 26 hours and events stored up to two days either side can show.
+func (z *ZonedStore) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	key := dayKey(date)
+	seen := make(map[string]bool)
+	var events []*model.Event
+	for _, offset := range []int{0, -1, 1, -2, 2} {
+		day := date.AddDate(0, 0, offset)
+		stored, err := z.store.LoadDayEvents(day)
+		if err != nil {
+			return nil, err
+		}
+		for _, evt := range stored {
+			shown := evt.InZone(day, z.loc)
+			if shown.DisplayZone == "" {
+				// Floating events stay on the day they were loaded for
+				if offset != 0 {
+					continue
+				}
+			} else if shown.StartDate != key && !shown.Covers(date) {
+				continue
+			}
+			id := shown.ID + "@" + shown.RecurrenceID + "@" + shown.StartDate
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			events = append(events, shown)
+		}
+	}
+	sortEvents(events, date)
+	return events, nil
+}
+
+// LoadRange returns the events between from and to in the display zone
+func (z *ZonedStore) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	return loadRange(from, to, z.LoadDayEvents)
+}
+
+// SaveEvent stores event, converting it back to its own zone if needed
+func (z *ZonedStore) SaveEvent(date time.Time, event *model.Event) error {
+	stored, day := event.FromDisplay(date)
+	if err := z.store.SaveEvent(day, stored); err != nil {
+		return err
+	}
+	event.ID = stored.ID
+	return nil
+}
+
+// UpdateEvent replaces oldEvent by newEvent, converting both back to
+// their own zones if needed. A single-day event whose new time falls on
+// another day in its own zone is moved to that day in one write.
+func (z *ZonedStore) UpdateEvent(date time.Time, oldEvent, newEvent *model.Event) error {
+	oldStored, day := oldEvent.FromDisplay(date)
+	newStored, newDay := newEvent.FromDisplay(date)
+	if dayKey(newDay) == dayKey(day) || newStored.IsMultiDay() || newStored.InSeries() {
+		return z.store.UpdateEvent(day, oldStored, newStored)
+	}
+	return moveEvent(z.store, day, newDay, oldStored, newStored)
+}
+
+// DeleteEvent removes event, converting it back to its own zone if needed
+func (z *ZonedStore) DeleteEvent(date time.Time, event *model.Event) error {
+	stored, day := event.FromDisplay(date)
+	return z.store.DeleteEvent(day, stored)
+}
+
+// LoadSeries returns the series and its overrides in their own zone
+func (z *ZonedStore) LoadSeries(id string) (*model.Event, []*model.Event, error) {
+	return z.store.LoadSeries(id)
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"bubblecal/internal/model"
+	"testing"
+	"time"
+	_ "time/tzdata" // the same zone data on every machine
+)
+
+func zone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func date(key string) time.Time {
+	d, _ := time.ParseInLocation(model.DateFormat, key, time.Local)
+	return d
+}
+
+// shownOn returns the events z shows on the day key
+func shownOn(t *testing.T, z *ZonedStore, key string) []*model.Event {
+	t.Helper()
+	events, err := z.LoadDayEvents(date(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events
+}
+
+func TestZonedStoreUpdateAcrossMidnight(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		store Store
+	}{
+		{"memory", NewMemoryStore()},
+		{"files", NewFileStore(t.TempDir())},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.store.SaveEvent(date("2025-08-13"), &model.Event{
+				StartTime: "08:00", EndTime: "09:00", Title: "Call", TimeZone: "America/New_York",
+			}); err != nil {
+				t.Fatal(err)
+			}
+			z := NewZonedStore(tt.store, zone(t, "Asia/Jerusalem"))
+			events := shownOn(t, z, "2025-08-13")
+			if len(events) != 1 || events[0].StartTime != "15:00" {
+				t.Fatalf("shown as %+v", events)
+			}
+
+			// 06:00 in Jerusalem is 23:00 the day before in New York
+			moved := events[0].Clone()
+			moved.StartTime, moved.EndTime = "06:00", "07:00"
+			if err := z.UpdateEvent(date("2025-08-13"), events[0], moved); err != nil {
+				t.Fatal(err)
+			}
+
+			events = shownOn(t, z, "2025-08-13")
+			if len(events) != 1 || events[0].StartTime != "06:00" || events[0].EndTime != "07:00" {
+				t.Errorf("shown on the 13th as %+v, want 06:00-07:00", events)
+			}
+			if events := shownOn(t, z, "2025-08-14"); len(events) != 0 {
+				t.Errorf("also shown on the 14th: %+v", events)
+			}
+			stored, _ := tt.store.LoadDayEvents(date("2025-08-12"))
+			if len(stored) != 1 || stored[0].StartTime != "23:00" {
+				t.Errorf("stored on the 12th as %+v, want 23:00", stored)
+			}
+			if fs, ok := tt.store.(*FileStore); ok {
+				if trash, _ := fs.LoadTrash(); len(trash) != 0 {
+					t.Errorf("moving the event left %d events in the trash", len(trash))
+				}
+			}
+		})
+	}
+}
+
+func TestZonedStoreFarApartZones(t *testing.T) {
+	store := NewMemoryStore()
+	// 00:30 on the 15th at UTC+14 is 22:30 on the 13th at UTC-12
+	if err := store.SaveEvent(date("2025-08-15"), &model.Event{
+		StartTime: "00:30", EndTime: "01:00", Title: "Early", TimeZone: "Pacific/Kiritimati",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	z := NewZonedStore(store, zone(t, "Etc/GMT+12"))
+	events := shownOn(t, z, "2025-08-13")
+	if len(events) != 1 || events[0].StartTime != "22:30" {
+		t.Errorf("shown on the 13th as %+v, want 22:30", events)
+	}
+	if events := shownOn(t, z, "2025-08-15"); len(events) != 0 {
+		t.Errorf("also shown on the 15th: %+v", events)
+	}
+}
@@ -14,6 +14,7 @@ import (
 type AgendaViewModel struct {
 	selectedDate  *time.Time
 	events        []*model.Event
+	tasks         []*model.Task // listed after the events
 	selectedIndex int
 	styles        *Styles
 	width         int
@@ -42,14 +43,57 @@ func (a *AgendaViewModel) SetSize(width, height int) {
 
 func (a *AgendaViewModel) SetEvents(events []*model.Event) {
 	a.events = events
-	if a.selectedIndex >= len(events) {
-		a.selectedIndex = len(events) - 1
+	a.clampSelection()
+}
+
+// SetTasks sets the tasks listed below the events
+func (a *AgendaViewModel) SetTasks(tasks []*model.Task) {
+	a.tasks = tasks
+	a.clampSelection()
+}
+
+func (a *AgendaViewModel) clampSelection() {
+	if a.selectedIndex >= a.rowCount() {
+		a.selectedIndex = a.rowCount() - 1
 	}
 	if a.selectedIndex < 0 {
 		a.selectedIndex = 0
 	}
 }
 
+// selectedKey identifies the selected row, see rowKey
+func (a *AgendaViewModel) selectedKey() string {
+	switch i := a.selectedIndex; {
+	case i >= 0 && i < len(a.events):
+		return rowKey(a.events[i], nil)
+	case i >= len(a.events) && i < a.rowCount():
+		return rowKey(nil, a.tasks[i-len(a.events)])
+	}
+	return ""
+}
+
+// selectKey selects the row selectedKey returned, if it is still listed
+func (a *AgendaViewModel) selectKey(key string) {
+	for i := 0; i < a.rowCount() && key != ""; i++ {
+		var k string
+		if i < len(a.events) {
+			k = rowKey(a.events[i], nil)
+		} else {
+			k = rowKey(nil, a.tasks[i-len(a.events)])
+		}
+		if k == key {
+			a.selectedIndex = i
+			a.ensureVisible()
+			return
+		}
+	}
+}
+
+// rowCount returns the number of events and tasks listed
+func (a *AgendaViewModel) rowCount() int {
+	return len(a.events) + len(a.tasks)
+}
+
 func (a *AgendaViewModel) MoveUp() {
 	if a.selectedIndex > 0 {
 		a.selectedIndex--
@@ -58,7 +102,7 @@ func (a *AgendaViewModel) MoveUp() {
 }
 
 func (a *AgendaViewModel) MoveDown() {
-	if a.selectedIndex < len(a.events)-1 {
+	if a.selectedIndex < a.rowCount()-1 {
 		a.selectedIndex++
 		a.ensureVisible()
 	}
@@ -70,8 +114,8 @@ func (a *AgendaViewModel) GoToTop() {
 }
 
 func (a *AgendaViewModel) GoToBottom() {
-	if len(a.events) > 0 {
-		a.selectedIndex = len(a.events) - 1
+	if a.rowCount() > 0 {
+		a.selectedIndex = a.rowCount() - 1
 		a.ensureVisible()
 	}
 }
@@ -82,7 +126,7 @@ func (a *AgendaViewModel) SetJumpMode(jumpMode bool, jumpKeys []string) {
 }
 
 func (a *AgendaViewModel) JumpToIndex(index int) {
-	if index >= 0 && index < len(a.events) {
+	if index >= 0 && index < a.rowCount() {
 		a.selectedIndex = index
 		a.ensureVisible()
 	}
@@ -95,12 +139,21 @@ func (a *AgendaViewModel) GetSelectedEvent() *model.Event {
 	return nil
 }
 
+// GetSelectedTask returns the selected task, or nil if an event or
+// nothing is selected
+func (a *AgendaViewModel) GetSelectedTask() *model.Task {
+	if i := a.selectedIndex - len(a.events); i >= 0 && i < len(a.tasks) {
+		return a.tasks[i]
+	}
+	return nil
+}
+
 func (a *AgendaViewModel) GetSelectedIndex() int {
 	return a.selectedIndex
 }
 
 func (a *AgendaViewModel) ensureVisible() {
-	if len(a.events) == 0 {
+	if a.rowCount() == 0 {
 		return
 	}
 	visibleHeight := a.height - 2 // Account for minimal padding
@@ -124,7 +177,7 @@ func (a *AgendaViewModel) View() string {
 	
 	var lines []string
 	
-	if len(a.events) == 0 {
+	if a.rowCount() == 0 {
 		noEvents := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
 			Width(a.width - 6).
@@ -135,13 +188,17 @@ func (a *AgendaViewModel) View() string {
 		visibleHeight := a.height - 2 // Account for borders/padding
 		a.ensureVisible() // Make sure selected item is visible
 		endIndex := a.scrollOffset + visibleHeight
-		if endIndex > len(a.events) {
-			endIndex = len(a.events)
+		if endIndex > a.rowCount() {
+			endIndex = a.rowCount()
 		}
 		
 		for i := a.scrollOffset; i < endIndex; i++ {
-			evt := a.events[i]
-			line := a.renderEventLine(evt, i == a.selectedIndex)
+			var line string
+			if i < len(a.events) {
+				line = a.renderEventLine(a.events[i], i == a.selectedIndex)
+			} else {
+				line = a.renderTaskLine(a.tasks[i-len(a.events)], i == a.selectedIndex)
+			}
 			
 			// Add jump key overlay if in jump mode
 			if a.jumpMode && i < len(a.jumpKeys) {
@@ -167,7 +224,7 @@ func (a *AgendaViewModel) View() string {
 				Render("↑ more")
 			lines = append([]string{scrollUp}, lines...)
 		}
-		if endIndex < len(a.events) {
+		if endIndex < a.rowCount() {
 			scrollDown := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("220")).
 				Width(a.width - 6).
@@ -193,31 +250,18 @@ func (a *AgendaViewModel) View() string {
 func (a *AgendaViewModel) renderEventLine(evt *model.Event, selected bool) string {
 	// Get category color
 	categoryColor := lipgloss.Color("15") // Default white
-	if a.config != nil && evt.Category != "" {
-		categoryColor = lipgloss.Color(a.config.GetCategoryColor(evt.Category))
+	if a.config != nil && evt.PrimaryCategory() != "" {
+		categoryColor = lipgloss.Color(a.config.GetCategoryColor(evt.PrimaryCategory()))
 	}
 	
-	var label string
-	if evt.IsAllDay() {
-		// All day event
-		allDayText := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("245")).
-			Render("All day")
-		titleText := lipgloss.NewStyle().
-			Foreground(categoryColor).
-			Render(evt.Title)
-		label = fmt.Sprintf("%s %s", allDayText, titleText)
-	} else {
-		// Timed event
-		var timeStr string
-		if evt.EndTime != "" {
-			timeStr = fmt.Sprintf("%s-%s", evt.StartTime, evt.EndTime)
-		} else {
-			timeStr = evt.StartTime
-		}
-		timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-		titleStyle := lipgloss.NewStyle().Foreground(categoryColor)
-		label = fmt.Sprintf("%s %s", timeStyle.Render(timeStr), titleStyle.Render(evt.Title))
+	// Time as seen on the selected date ("All day", "09:00-10:00", "→12:00 (3/3)")
+	timeStr := eventTimeLabel(evt, *a.selectedDate)
+	timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	titleStyle := withStatus(lipgloss.NewStyle().Foreground(categoryColor), evt)
+	label := fmt.Sprintf("%s %s%s", timeStyle.Render(timeStr), titleStyle.Render(eventTitle(evt)), categoryDots(a.config, evt))
+	// Location and link go in whatever room the line has left
+	if details := eventDetails(evt, false); details != "" {
+		label += timeStyle.Render(truncateText(details, a.width-6-lipgloss.Width(label)))
 	}
 	
 	// Build the final string with selection indicator
@@ -235,4 +279,18 @@ func (a *AgendaViewModel) renderEventLine(evt *model.Event, selected bool) strin
 	return lipgloss.NewStyle().
 		Width(a.width - 4).
 		Render("  " + label)
-}
\ No newline at end of file
+}
+func (a *AgendaViewModel) renderTaskLine(t *model.Task, selected bool) string {
+	label := taskLabel(a.config, t, *a.selectedDate)
+	if selected {
+		return lipgloss.NewStyle().
+			Background(lipgloss.Color("238")).
+			Foreground(lipgloss.Color("15")).
+			Bold(true).
+			Width(a.width - 4).
+			Render("▶ " + label)
+	}
+	return lipgloss.NewStyle().
+		Width(a.width - 4).
+		Render("  " + label)
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"bubblecal/internal/config"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// categoryFilter is a store that hides events without a given category.
+// The views read through it, so a filter applies everywhere at once;
+// writes go straight to the wrapped store.
+type categoryFilter struct {
+	storage.Store
+	tasks    storage.TaskStore // nil if the store doesn't keep tasks
+	notes    storage.NoteStore // nil if the store doesn't keep notes
+	category string            // "" shows every event
+}
+
+// LoadDayEvents returns the events on date that pass the filter
+func (f *categoryFilter) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	events, err := f.Store.LoadDayEvents(date)
+	if err != nil || f.category == "" {
+		return events, err
+	}
+	var visible []*model.Event
+	for _, evt := range events {
+		if evt.HasCategory(f.category) {
+			visible = append(visible, evt)
+		}
+	}
+	return visible, nil
+}
+
+// LoadRange returns the events between from and to that pass the filter
+func (f *categoryFilter) LoadRange(from, to time.Time) (map[string][]*model.Event, error) {
+	result := make(map[string][]*model.Event)
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		events, err := f.LoadDayEvents(date)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			result[date.Format(model.DateFormat)] = events
+		}
+	}
+	return result, nil
+}
+
+// LoadTasks returns the tasks that pass the filter
+func (f *categoryFilter) LoadTasks() ([]*model.Task, error) {
+	if f.tasks == nil {
+		return nil, nil
+	}
+	tasks, err := f.tasks.LoadTasks()
+	if err != nil || f.category == "" {
+		return tasks, err
+	}
+	var visible []*model.Task
+	for _, t := range tasks {
+		if strings.EqualFold(t.Category, f.category) {
+			visible = append(visible, t)
+		}
+	}
+	return visible, nil
+}
+
+// SaveTask saves a task in the wrapped store
+func (f *categoryFilter) SaveTask(task *model.Task) error {
+	if f.tasks == nil {
+		return fmt.Errorf("this calendar doesn't keep tasks")
+	}
+	return f.tasks.SaveTask(task)
+}
+
+// DeleteTask deletes a task from the wrapped store
+func (f *categoryFilter) DeleteTask(task *model.Task) error {
+	if f.tasks == nil {
+		return fmt.Errorf("this calendar doesn't keep tasks")
+	}
+	return f.tasks.DeleteTask(task)
+}
+
+// LoadNote returns a day's note from the wrapped store; notes aren't
+// filtered
+func (f *categoryFilter) LoadNote(date time.Time) (string, error) {
+	if f.notes == nil {
+		return "", nil
+	}
+	return f.notes.LoadNote(date)
+}
+
+// SaveNote saves a day's note in the wrapped store
+func (f *categoryFilter) SaveNote(date time.Time, note string) error {
+	if f.notes == nil {
+		return fmt.Errorf("this calendar doesn't keep notes")
+	}
+	return f.notes.SaveNote(date, note)
+}
+
+// Location returns the display zone of the wrapped store
+func (f *categoryFilter) Location() *time.Location {
+	if z, ok := f.Store.(interface{ Location() *time.Location }); ok {
+		return z.Location()
+	}
+	return time.Local
+}
+
+// next moves the filter to the category after the current one, and back
+// to showing everything after the last
+func (f *categoryFilter) next(categories []config.Category) {
+	for i, cat := range categories {
+		if cat.Name == f.category {
+			if i+1 < len(categories) {
+				f.category = categories[i+1].Name
+			} else {
+				f.category = ""
+			}
+			return
+		}
+	}
+	if f.category == "" && len(categories) > 0 {
+		f.category = categories[0].Name
+	} else {
+		f.category = ""
+	}
+}
+
+// categoryDots renders a colored dot for each category after the primary
+// one, which already colors the title
+func categoryDots(cfg *config.Config, evt *model.Event) string {
+	if len(evt.Categories) < 2 {
+		return ""
+	}
+	dots := ""
+	for _, name := range evt.Categories[1:] {
+		color := "#808080"
+		if cfg != nil {
+			color = cfg.GetCategoryColor(name)
+		}
+		dots += lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render("●")
+	}
+	return " " + dots
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"context"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// calendarData is what the views draw from: the events and notes of a
+// range of days, and the tasks. It is loaded off the render path by
+// loadDataCmd, so View methods never touch the store.
+type calendarData struct {
+	from, to time.Time // days loaded, inclusive
+	days     map[string][]*model.Event
+	notes    map[string]string
+	tasks    []*model.Task
+}
+
+// covers reports whether every day from from to to has been loaded
+func (d *calendarData) covers(from, to time.Time) bool {
+	return d.days != nil &&
+		d.from.Format(model.DateFormat) <= from.Format(model.DateFormat) &&
+		d.to.Format(model.DateFormat) >= to.Format(model.DateFormat)
+}
+
+// eventsOn returns the loaded events of date
+func (d *calendarData) eventsOn(date time.Time) []*model.Event {
+	return d.days[date.Format(model.DateFormat)]
+}
+
+// noteOn returns the loaded note of date
+func (d *calendarData) noteOn(date time.Time) string {
+	return d.notes[date.Format(model.DateFormat)]
+}
+
+// rowKey identifies an event or task row across reloads, so a selection
+// can follow its row when the rows around it change
+func rowKey(evt *model.Event, t *model.Task) string {
+	switch {
+	case t != nil:
+		return "task:" + t.ID
+	case evt != nil:
+		return evt.ID + "@" + evt.RecurrenceID + "@" + evt.StartDate
+	}
+	return ""
+}
+
+// DataLoadedMsg carries the result of a loadDataCmd
+type DataLoadedMsg struct {
+	seq  int
+	data calendarData
+}
+
+// loadDataCmd loads the days from from to to, the notes of those days and
+// the tasks. It gives up without a message once ctx is cancelled, which
+// happens when the user has moved on before it finished.
+func loadDataCmd(ctx context.Context, seq int, store storage.Store, from, to time.Time) tea.Cmd {
+	return func() tea.Msg {
+		data := calendarData{
+			from:  from,
+			to:    to,
+			days:  make(map[string][]*model.Event),
+			notes: make(map[string]string),
+		}
+		notes, _ := store.(storage.NoteStore)
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if ctx.Err() != nil {
+				return nil
+			}
+			key := date.Format(model.DateFormat)
+			if events, err := store.LoadDayEvents(date); err == nil && len(events) > 0 {
+				data.days[key] = events
+			}
+			if notes != nil {
+				if note, err := notes.LoadNote(date); err == nil && note != "" {
+					data.notes[key] = note
+				}
+			}
+		}
+		if tasks, ok := store.(storage.TaskStore); ok {
+			data.tasks, _ = tasks.LoadTasks()
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		return DataLoadedMsg{seq: seq, data: data}
+	}
+}
+
+// startOfDay returns midnight at the start of date
+func startOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+}
+
+// monthGridRange returns the first and last day shown in the month grid
+// around date, which includes date's week
+func monthGridRange(date time.Time) (time.Time, time.Time) {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	start := first.AddDate(0, 0, -int(first.Weekday()))
+	return start, start.AddDate(0, 0, 41)
+}
+
+// listRange returns the days the List view shows: the past week and the
+// next days days
+func listRange(today time.Time, days int) (time.Time, time.Time) {
+	start := startOfDay(today).AddDate(0, 0, -7)
+	return start, start.AddDate(0, 0, days+6)
+}
+
+// loadingView is drawn in place of a view whose days haven't loaded yet
+func loadingView(width, height int) string {
+	return lipgloss.Place(width-4, height-2, lipgloss.Center, lipgloss.Center,
+		lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Loading…"))
+}
@@ -3,13 +3,19 @@ package tui
 import (
 	"fmt"
 	"bubblecal/internal/config"
-	"bubblecal/internal/storage"
+	"bubblecal/internal/model"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Working hours the day view reports free time for
+const (
+	workdayStart = "09:00"
+	workdayEnd   = "18:00"
+)
+
 // DayViewModel represents the day view
 type DayViewModel struct {
 	selectedDate *time.Time
@@ -18,15 +24,17 @@ type DayViewModel struct {
 	width        int
 	height       int
 	config       *config.Config
+	data         *calendarData
 }
 
 // NewDayViewModel creates a new day view model
-func NewDayViewModel(selectedDate *time.Time, selectedHour *int, styles *Styles, config *config.Config) *DayViewModel {
+func NewDayViewModel(selectedDate *time.Time, selectedHour *int, styles *Styles, config *config.Config, data *calendarData) *DayViewModel {
 	return &DayViewModel{
 		selectedDate: selectedDate,
 		selectedHour: selectedHour,
 		styles:       styles,
 		config:       config,
+		data:         data,
 	}
 }
 
@@ -41,6 +49,9 @@ func (d *DayViewModel) View() string {
 	}
 	
 	date := *d.selectedDate
+	if !d.data.covers(date, date) {
+		return loadingView(d.width, d.height)
+	}
 	var lines []string
 	
 	// Date header
@@ -54,8 +65,7 @@ func (d *DayViewModel) View() string {
 	lines = append(lines, dateHeader)
 	lines = append(lines, strings.Repeat("─", d.width-4))
 	
-	// Load events
-	events, _ := storage.LoadDayEvents(date)
+	events := d.data.eventsOn(date)
 	
 	// Create hour map - store events with color information
 	type coloredEvent struct {
@@ -64,44 +74,46 @@ func (d *DayViewModel) View() string {
 	}
 	hourEvents := make(map[int][]coloredEvent)
 	var allDayEvents []string
+	lastHour := 0 // last hour covered by events running past midnight
 	
 	for _, evt := range events {
-		if evt.IsAllDay() {
+		seg := evt.SegmentOn(date)
+		if seg.AllDay {
 			// Get category color for all-day events
 			categoryColor := lipgloss.Color("15") // Default white
-			if d.config != nil && evt.Category != "" {
-				categoryColor = lipgloss.Color(d.config.GetCategoryColor(evt.Category))
+			if d.config != nil && evt.PrimaryCategory() != "" {
+				categoryColor = lipgloss.Color(d.config.GetCategoryColor(evt.PrimaryCategory()))
 			}
-			coloredTitle := lipgloss.NewStyle().
-				Foreground(categoryColor).
-				Render(evt.Title)
+			title := eventTitle(evt)
+			if evt.IsMultiDay() {
+				title = fmt.Sprintf("%s %s", title, spanDayLabel(evt, date))
+			}
+			coloredTitle := withStatus(lipgloss.NewStyle().Foreground(categoryColor), evt).
+				Render(title)
 			allDayEvents = append(allDayEvents, coloredTitle)
 		} else {
-			var hour int
-			if _, err := fmt.Sscanf(evt.StartTime, "%d:", &hour); err == nil {
+			if hour, last, ok := seg.HourRange(); ok {
+				if seg.ContinuesAfter && last > lastHour {
+					lastHour = last
+				}
 				// Get category color
 				categoryColor := lipgloss.Color("15") // Default white
-				if d.config != nil && evt.Category != "" {
-					categoryColor = lipgloss.Color(d.config.GetCategoryColor(evt.Category))
+				if d.config != nil && evt.PrimaryCategory() != "" {
+					categoryColor = lipgloss.Color(d.config.GetCategoryColor(evt.PrimaryCategory()))
 				}
 				
 				// Build time part in gray
-				timeStr := ""
-				if evt.EndTime != "" {
-					timeStr = fmt.Sprintf("%s-%s", evt.StartTime, evt.EndTime)
-				} else {
-					timeStr = evt.StartTime
-				}
+				timeStr := eventTimeLabel(evt, date)
 				
 				// Build the event display with colored title
 				timeStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
-				titleStyle := lipgloss.NewStyle().Foreground(categoryColor)
+				titleStyle := withStatus(lipgloss.NewStyle().Foreground(categoryColor), evt)
 				
-				eventText := fmt.Sprintf("%s %s", timeStyle.Render(timeStr), titleStyle.Render(evt.Title))
-				if evt.Category != "" {
+				eventText := fmt.Sprintf("%s %s", timeStyle.Render(timeStr), titleStyle.Render(eventTitle(evt)))
+				if len(evt.Categories) > 0 {
 					categoryLabel := lipgloss.NewStyle().
 						Foreground(lipgloss.Color("240")).
-						Render(fmt.Sprintf(" [%s]", evt.Category))
+						Render(fmt.Sprintf(" [%s]", strings.Join(evt.Categories, ", ")))
 					eventText += categoryLabel
 				}
 				
@@ -113,6 +125,13 @@ func (d *DayViewModel) View() string {
 		}
 	}
 	
+	// Free time during working hours; cancelled events don't count
+	lines = append(lines, lipgloss.NewStyle().
+		Width(d.width - 4).
+		Align(lipgloss.Center).
+		Foreground(lipgloss.Color("240")).
+		Render(freeTimeLabel(model.FreeSlots(events, date, workdayStart, workdayEnd))))
+	
 	// All-day events
 	if len(allDayEvents) > 0 {
 		// Calculate height for all-day section
@@ -151,6 +170,9 @@ func (d *DayViewModel) View() string {
 			endHour = hour
 		}
 	}
+	if lastHour > endHour {
+		endHour = lastHour
+	}
 	
 	// Ensure we don't go beyond reasonable bounds
 	if startHour < 0 {
@@ -160,8 +182,8 @@ func (d *DayViewModel) View() string {
 		endHour = 23
 	}
 	
-	currentHour := time.Now().Hour()
-	isToday := sameDay(date, time.Now())
+	currentHour := currentTime(d.config).Hour()
+	isToday := sameDay(date, currentTime(d.config))
 	
 	// Just show all hours - let the content flow naturally
 	for h := startHour; h <= endHour; h++ {
@@ -227,4 +249,29 @@ func (d *DayViewModel) GetSelectedHour() string {
 		return fmt.Sprintf("%02d:00", *d.selectedHour)
 	}
 	return ""
-}
\ No newline at end of file
+}
+// freeTimeLabel summarizes free slots: "Free 09:00-10:00 · 14:00-18:00 (5h)"
+func freeTimeLabel(slots []model.Slot) string {
+	if len(slots) == 0 {
+		return "No free time " + workdayStart + "-" + workdayEnd
+	}
+	var parts []string
+	var total time.Duration
+	for _, s := range slots {
+		parts = append(parts, s.Start+"-"+s.End)
+		total += s.Duration()
+	}
+	return fmt.Sprintf("Free %s (%s)", strings.Join(parts, " · "), formatDuration(total))
+}
+
+// formatDuration formats a duration as "5h", "45m" or "2h30m"
+func formatDuration(d time.Duration) string {
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
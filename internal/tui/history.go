@@ -0,0 +1,257 @@
+package tui
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// committedMsg reports a failed commit
+type committedMsg struct {
+	err error
+}
+
+// commitCmd commits the changes written through git, if any, off the
+// update loop, since git can take a while
+func commitCmd(git *storage.GitStore) tea.Cmd {
+	if git == nil || !git.Pending() {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := git.Commit(); err != nil {
+			return committedMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// historyLoadedMsg carries the versions of the event a HistoryModal shows,
+// and the event as it is stored now
+type historyLoadedMsg struct {
+	current  *model.Event
+	day      time.Time // the day current is stored on
+	versions []*storage.EventVersion
+	err      error
+}
+
+// loadHistoryCmd reads the history of evt, shown on date. Occurrences of
+// a recurring series show the history of the series.
+func loadHistoryCmd(git *storage.GitStore, store storage.Store, date time.Time, evt *model.Event) tea.Cmd {
+	return func() tea.Msg {
+		current, day := evt.FromDisplay(date)
+		if current.InSeries() && !current.IsOverride() {
+			series, _, err := store.LoadSeries(current.SeriesKey())
+			if err != nil {
+				return historyLoadedMsg{err: err}
+			}
+			current = series
+			day, _ = time.ParseInLocation(model.DateFormat, series.StartDate, time.Local)
+		}
+		versions, err := git.History(day, current.ID)
+		return historyLoadedMsg{current: current, day: day, versions: versions, err: err}
+	}
+}
+
+// restoreVersion puts v back in place of current, stored on day, through
+// store so it can be undone
+func restoreVersion(store storage.Store, day time.Time, current *model.Event, v *storage.EventVersion) error {
+	restored := v.Event.Clone()
+	if restored.Extra == nil {
+		restored.Extra = []model.Property{} // drop keys added since
+	}
+	if v.Date.Format(model.DateFormat) == day.Format(model.DateFormat) {
+		return store.UpdateEvent(day, current, restored)
+	}
+	// The event has moved since; move it back
+	if err := store.DeleteEvent(day, current); err != nil {
+		return err
+	}
+	return store.SaveEvent(v.Date, restored)
+}
+
+// HistoryModal lists the committed versions of an event to restore one
+type HistoryModal struct {
+	git      *storage.GitStore
+	store    storage.Store // restored versions are saved through it
+	date     time.Time
+	event    *model.Event // as shown when the modal was opened
+	current  *model.Event
+	day      time.Time
+	versions []*storage.EventVersion
+	loaded   bool
+	selected int
+	message  string
+	errorMsg string
+	styles   *Styles
+	width    int
+	height   int
+}
+
+func NewHistoryModal(git *storage.GitStore, store storage.Store, date time.Time, evt *model.Event, styles *Styles) *HistoryModal {
+	return &HistoryModal{
+		git:    git,
+		store:  store,
+		date:   date,
+		event:  evt,
+		styles: styles,
+	}
+}
+
+func (m *HistoryModal) Init() tea.Cmd {
+	return loadHistoryCmd(m.git, m.store, m.date, m.event)
+}
+
+func (m *HistoryModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case historyLoadedMsg:
+		m.loaded = true
+		m.errorMsg = ""
+		if msg.err != nil {
+			m.errorMsg = msg.err.Error()
+			return m, nil
+		}
+		m.current, m.day, m.versions = msg.current, msg.day, msg.versions
+		// Later loads follow the event wherever it is now
+		m.event, m.date = m.current, m.day
+		if m.selected >= len(m.versions) {
+			m.selected = len(m.versions) - 1
+		}
+		if m.selected < 0 {
+			m.selected = 0
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+
+		case "down", "j":
+			if m.selected < len(m.versions)-1 {
+				m.selected++
+			}
+
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+
+		case "enter", "r":
+			if m.selected < len(m.versions) && m.current != nil {
+				v := m.versions[m.selected]
+				m.errorMsg = ""
+				if err := restoreVersion(m.store, m.day, m.current, v); err != nil {
+					m.errorMsg = err.Error()
+					return m, nil
+				}
+				m.message = "Restored the version from " + v.When.Format("Mon Jan 2 15:04")
+				m.current = nil // until the history is read again
+				return m, loadHistoryCmd(m.git, m.store, v.Date, v.Event)
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *HistoryModal) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	gray := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	content := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33")).Render("🕘 History of " + m.event.Title),
+		"",
+	}
+
+	switch {
+	case !m.loaded:
+		content = append(content, gray.Render("Loading…"))
+	case len(m.versions) == 0 && m.errorMsg == "":
+		content = append(content, gray.Render("No committed versions yet"))
+	default:
+		// Keep the selected version in view, leaving room for its preview
+		rows := m.height - 22
+		if rows < 5 {
+			rows = 5
+		}
+		first := 0
+		if m.selected >= rows {
+			first = m.selected - rows + 1
+		}
+		for i, v := range m.versions {
+			if i < first || i >= first+rows {
+				continue
+			}
+			line := v.When.Format("Mon Jan 2 2006 15:04") + "  " + v.Message
+			if i == m.selected {
+				line = lipgloss.NewStyle().
+					Background(lipgloss.Color("238")).
+					Foreground(lipgloss.Color("15")).
+					Bold(true).
+					Render("▶ " + line)
+			} else {
+				line = "  " + line
+			}
+			content = append(content, line)
+		}
+		if m.selected < len(m.versions) {
+			content = append(content, "", m.preview(m.versions[m.selected]))
+		}
+	}
+
+	content = append(content, "")
+	if m.message != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓ "+m.message), "")
+	}
+	if m.errorMsg != "" {
+		content = append(content, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true).
+			Render("❌ "+m.errorMsg), "")
+	}
+	content = append(content, gray.Render("Enter Restore this version · ↑↓ Select · Esc Close"))
+
+	modal := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2).
+		Width(80).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+// preview describes the event as version v left it
+func (m *HistoryModal) preview(v *storage.EventVersion) string {
+	evt := v.Event
+	lines := []string{
+		fmt.Sprintf("%s  %s  %s", v.Date.Format("Mon Jan 2 2006"), eventTimeLabel(evt, v.Date), eventTitle(evt)),
+	}
+	if evt.Location != "" {
+		lines = append(lines, "Location: "+evt.Location)
+	}
+	if evt.Status != "" {
+		lines = append(lines, "Status: "+evt.Status)
+	}
+	if evt.Description != "" {
+		desc := strings.Split(evt.Description, "\n")
+		if len(desc) > 3 {
+			desc = append(desc[:3], "…")
+		}
+		lines = append(lines, desc...)
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder(), false, false, false, true).
+		BorderForeground(lipgloss.Color("240")).
+		PaddingLeft(1).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// eventDetails summarizes an event's location, attendees and link for
+// event lines; withAttendees adds the number of attendees
+func eventDetails(evt *model.Event, withAttendees bool) string {
+	var parts []string
+	if evt.Location != "" {
+		parts = append(parts, "📍 "+evt.Location)
+	}
+	if withAttendees && len(evt.Attendees) > 0 {
+		parts = append(parts, fmt.Sprintf("👥 %d", len(evt.Attendees)))
+	}
+	if evt.URL != "" {
+		parts = append(parts, "🔗")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "  " + strings.Join(parts, " · ")
+}
+
+// isValidURL reports whether s is a link we are willing to open
+func isValidURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.Host != ""
+	case "mailto", "tel":
+		return u.Opaque != ""
+	}
+	return false
+}
+
+// openURL opens a link in the system's default handler without waiting
+// for it
+func openURL(link string) error {
+	if !isValidURL(link) {
+		return fmt.Errorf("not a link: %s", link)
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open link: %w", err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// clipboardCommands are tried in order until one is installed
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// copyToClipboard puts text on the system clipboard
+func copyToClipboard(text string) error {
+	for _, args := range clipboardCommands {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to copy: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard tool found")
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"bubblecal/internal/config"
 	"bubblecal/internal/model"
-	"bubblecal/internal/storage"
 	"strings"
 	"time"
 
@@ -18,12 +17,14 @@ type ListViewModel struct {
 	width         int
 	height        int
 	config        *config.Config
+	data          *calendarData
 	selectedIndex int
 	scrollOffset  int
 	daysToShow    int // Number of days to display
-	events        map[string][]*model.Event // Events grouped by date
+	events        map[string][]EventWithDate // Events and tasks grouped by date
 	dateOrder     []time.Time // Ordered list of dates
 	flatEvents    []EventWithDate // Flattened list for navigation
+	notes         map[string]string // Day note excerpts, if config.ListNotes is set
 	// Jump mode
 	jumpMode      bool
 	jumpKeys      []string
@@ -33,18 +34,20 @@ type ListViewModel struct {
 type EventWithDate struct {
 	Date  time.Time
 	Event *model.Event
+	Task  *model.Task // set instead of Event for task rows
 }
 
 // NewListViewModel creates a new list view model
-func NewListViewModel(selectedDate *time.Time, styles *Styles, config *config.Config) *ListViewModel {
+func NewListViewModel(selectedDate *time.Time, styles *Styles, config *config.Config, data *calendarData) *ListViewModel {
 	return &ListViewModel{
 		selectedDate:  selectedDate,
 		styles:        styles,
 		config:        config,
+		data:          data,
 		selectedIndex: 0,
 		scrollOffset:  0,
 		daysToShow:    30, // Show 30 days by default
-		events:        make(map[string][]*model.Event),
+		events:        make(map[string][]EventWithDate),
 	}
 }
 
@@ -53,42 +56,51 @@ func (l *ListViewModel) SetSize(width, height int) {
 	l.height = height
 }
 
+// LoadEvents rebuilds the rows from the loaded calendar data
 func (l *ListViewModel) LoadEvents() {
-	l.events = make(map[string][]*model.Event)
+	l.events = make(map[string][]EventWithDate)
 	l.dateOrder = []time.Time{}
 	l.flatEvents = []EventWithDate{}
+	l.notes = make(map[string]string)
 	
 	// Start from a week ago to show recent past events too
-	startDate := time.Now().AddDate(0, 0, -7)
-	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	today := currentTime(l.config)
+	startDate, endDate := listRange(today, l.daysToShow)
 	
-	// Load events for past week + next N days
-	totalDays := l.daysToShow + 7
-	for i := 0; i < totalDays; i++ {
-		date := startDate.AddDate(0, 0, i)
+	// Past week + next N days
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
 		dateKey := date.Format("2006-01-02")
 		
-		events, _ := storage.LoadDayEvents(date)
-		// Only show dates with events
-		if len(events) > 0 {
-			l.events[dateKey] = events
+		var rows []EventWithDate
+		for _, evt := range l.data.eventsOn(date) {
+			rows = append(rows, EventWithDate{Date: date, Event: evt})
+		}
+		// Tasks follow the events; overdue ones are listed today
+		for _, t := range model.TasksOn(l.data.tasks, date, today) {
+			rows = append(rows, EventWithDate{Date: date, Task: t})
+		}
+		
+		if l.config != nil && l.config.ListNotes {
+			if excerpt := noteExcerpt(l.data.noteOn(date)); excerpt != "" {
+				l.notes[dateKey] = excerpt
+			}
+		}
+		
+		// Only show dates with events, tasks or notes
+		if len(rows) > 0 || l.notes[dateKey] != "" {
+			l.events[dateKey] = rows
 			l.dateOrder = append(l.dateOrder, date)
 			
 			// Add to flat list for navigation
-			for _, evt := range events {
-				l.flatEvents = append(l.flatEvents, EventWithDate{
-					Date:  date,
-					Event: evt,
-				})
-			}
+			l.flatEvents = append(l.flatEvents, rows...)
 		}
 	}
 	
 	// If no events found, show a message
 	if len(l.dateOrder) == 0 {
-		today := time.Now()
+		today := currentTime(l.config)
 		l.dateOrder = append(l.dateOrder, today)
-		l.events[today.Format("2006-01-02")] = []*model.Event{}
+		l.events[today.Format("2006-01-02")] = nil
 	}
 }
 
@@ -161,13 +173,44 @@ func (l *ListViewModel) PageDown() {
 	l.ensureVisible()
 }
 
+// GetSelectedEvent returns the selected event, or nil if a task or
+// nothing is selected
 func (l *ListViewModel) GetSelectedEvent() *EventWithDate {
-	if l.selectedIndex >= 0 && l.selectedIndex < len(l.flatEvents) {
+	if l.selectedIndex >= 0 && l.selectedIndex < len(l.flatEvents) && l.flatEvents[l.selectedIndex].Event != nil {
 		return &l.flatEvents[l.selectedIndex]
 	}
 	return nil
 }
 
+// GetSelectedTask returns the selected task, or nil if an event or
+// nothing is selected
+func (l *ListViewModel) GetSelectedTask() *model.Task {
+	if l.selectedIndex >= 0 && l.selectedIndex < len(l.flatEvents) {
+		return l.flatEvents[l.selectedIndex].Task
+	}
+	return nil
+}
+
+// selectedKey identifies the selected row, see rowKey
+func (l *ListViewModel) selectedKey() string {
+	if l.selectedIndex >= 0 && l.selectedIndex < len(l.flatEvents) {
+		row := l.flatEvents[l.selectedIndex]
+		return row.Date.Format(model.DateFormat) + "/" + rowKey(row.Event, row.Task)
+	}
+	return ""
+}
+
+// selectKey selects the row selectedKey returned, if it is still listed
+func (l *ListViewModel) selectKey(key string) {
+	for i, row := range l.flatEvents {
+		if key != "" && row.Date.Format(model.DateFormat)+"/"+rowKey(row.Event, row.Task) == key {
+			l.selectedIndex = i
+			l.ensureVisible()
+			return
+		}
+	}
+}
+
 func (l *ListViewModel) ensureVisible() {
 	// Build the line index for each event
 	currentLine := 0
@@ -178,10 +221,13 @@ func (l *ListViewModel) ensureVisible() {
 		dateKey := date.Format("2006-01-02")
 		events := l.events[dateKey]
 		
-		// Date header takes one line
+		// Date header takes one line, and so does a note excerpt
 		currentLine++
+		if l.notes[dateKey] != "" {
+			currentLine++
+		}
 		
-		if len(events) == 0 {
+		if len(events) == 0 && l.notes[dateKey] == "" {
 			// "No upcoming events" message
 			currentLine++
 		} else {
@@ -226,8 +272,11 @@ func (l *ListViewModel) View() string {
 	if l.width == 0 || l.height == 0 {
 		return ""
 	}
+	if !l.data.covers(listRange(currentTime(l.config), l.daysToShow)) {
+		return loadingView(l.width, l.height)
+	}
 	
-	// Reload events
+	// Rebuild the rows from the loaded data
 	l.LoadEvents()
 	
 	var allLines []string
@@ -240,18 +289,30 @@ func (l *ListViewModel) View() string {
 		
 		// Date header
 		allLines = append(allLines, l.renderDateHeader(date))
+		if excerpt := l.notes[dateKey]; excerpt != "" {
+			allLines = append(allLines, lipgloss.NewStyle().
+				Foreground(lipgloss.Color("245")).
+				Italic(true).
+				PaddingLeft(2).
+				Render(truncateText(noteMarker+" "+excerpt, l.width-6)))
+		}
 		
-		if len(events) == 0 {
+		if len(events) == 0 && l.notes[dateKey] == "" {
 			// Special case: no events at all in the date range
 			noEventsStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("240")).
 				PaddingLeft(2)
 			allLines = append(allLines, noEventsStyle.Render("No upcoming events"))
 		} else {
-			// Render each event
-			for _, evt := range events {
+			// Render each event and task
+			for _, row := range events {
 				isSelected := eventIndex == l.selectedIndex
-				line := l.renderEventLine(evt, date, isSelected)
+				var line string
+				if row.Task != nil {
+					line = l.renderTaskLine(row.Task, date, isSelected)
+				} else {
+					line = l.renderEventLine(row.Event, date, isSelected)
+				}
 				
 				// Add jump key overlay if in jump mode
 				if l.jumpMode && eventIndex < len(l.jumpKeys) {
@@ -322,7 +383,7 @@ func (l *ListViewModel) View() string {
 }
 
 func (l *ListViewModel) renderDateHeader(date time.Time) string {
-	today := time.Now()
+	today := currentTime(l.config)
 	tomorrow := today.AddDate(0, 0, 1)
 	yesterday := today.AddDate(0, 0, -1)
 	
@@ -352,37 +413,32 @@ func (l *ListViewModel) renderDateHeader(date time.Time) string {
 func (l *ListViewModel) renderEventLine(evt *model.Event, date time.Time, selected bool) string {
 	// Get category color
 	categoryColor := lipgloss.Color("15") // Default white
-	if l.config != nil && evt.Category != "" {
-		categoryColor = lipgloss.Color(l.config.GetCategoryColor(evt.Category))
+	if l.config != nil && evt.PrimaryCategory() != "" {
+		categoryColor = lipgloss.Color(l.config.GetCategoryColor(evt.PrimaryCategory()))
 	}
 	
 	// Build event time string
-	var timeStr string
-	if evt.IsAllDay() {
-		timeStr = "All day"
-	} else if evt.EndTime != "" {
-		timeStr = fmt.Sprintf("%s-%s", evt.StartTime, evt.EndTime)
-	} else {
-		timeStr = evt.StartTime
-	}
+	timeStr := eventTimeLabel(evt, date)
 	
 	// Time styling
 	timeStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("245")).
-		Width(13) // Fixed width for alignment
+		Width(19) // Fixed width for alignment, fits "18:00→ (10/12)"
 	
 	// Title styling with category color
-	titleStyle := lipgloss.NewStyle().
-		Foreground(categoryColor)
+	titleStyle := withStatus(lipgloss.NewStyle().Foreground(categoryColor), evt)
 	
 	// Title and category
-	titleStr := evt.Title
-	if evt.Category != "" {
-		titleStr = fmt.Sprintf("%s [%s]", evt.Title, evt.Category)
+	titleStr := eventTitle(evt)
+	if len(evt.Categories) > 0 {
+		titleStr = fmt.Sprintf("%s [%s]", titleStr, strings.Join(evt.Categories, ", "))
 	}
 	
 	// Build the complete line
 	line := fmt.Sprintf("%s %s", timeStyle.Render(timeStr), titleStyle.Render(titleStr))
+	if details := eventDetails(evt, true); details != "" {
+		line += lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render(truncateText(details, l.width-6-lipgloss.Width(line)))
+	}
 	
 	// Apply selection styling
 	lineStyle := lipgloss.NewStyle().
@@ -400,4 +456,19 @@ func (l *ListViewModel) renderEventLine(evt *model.Event, date time.Time, select
 	}
 	
 	return lineStyle.Render(line)
-}
\ No newline at end of file
+}
+func (l *ListViewModel) renderTaskLine(t *model.Task, date time.Time, selected bool) string {
+	// Line the checkbox up with the event titles
+	line := strings.Repeat(" ", 20) + taskLabel(l.config, t, date)
+	lineStyle := lipgloss.NewStyle().
+		PaddingLeft(2).
+		Width(l.width - 2)
+	if selected {
+		lineStyle = lineStyle.
+			Background(lipgloss.Color("238")).
+			Foreground(lipgloss.Color("15")).
+			Bold(true)
+		return lineStyle.Render("▶ " + line)
+	}
+	return lineStyle.Render("  " + line)
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -25,6 +26,12 @@ const (
 	FieldEndTime
 	FieldCategory
 	FieldDescription
+	FieldEndDate
+	FieldRepeat
+	FieldTimeZone
+	FieldLocation
+	FieldURL
+	FieldAttendees
 )
 
 // EventModal for creating/editing events
@@ -32,6 +39,7 @@ type EventModal struct {
 	date            time.Time
 	editingEvent    *model.Event
 	inputs          []textinput.Model
+	description     textarea.Model // multi-line, so it isn't one of the inputs
 	focusedField    FieldType
 	allDay          bool
 	styles          *Styles
@@ -39,19 +47,28 @@ type EventModal struct {
 	height          int
 	errorMsg        string
 	categories      []config.Category
-	selectedCatIdx  int
+	selectedCatIdx  int      // category under the cursor in the selector
+	chosenCats      []string // selected category names, primary first
 	categoryMode    bool
+	scopeMode       bool // asking which occurrences of a series to change
+	scopeIdx        int
+	store           storage.Store
 }
 
 const (
 	inputTitle = iota
 	inputStartTime
 	inputEndTime
-	inputDescription
+	inputEndDate
+	inputRepeat
+	inputTimeZone
+	inputLocation
+	inputURL
+	inputAttendees
 )
 
-func NewEventModalWithTime(date time.Time, event *model.Event, defaultTime string, styles *Styles, categories []config.Category) *EventModal {
-	m := NewEventModal(date, event, styles, categories)
+func NewEventModalWithTime(date time.Time, event *model.Event, defaultTime string, styles *Styles, categories []config.Category, store storage.Store) *EventModal {
+	m := NewEventModal(date, event, styles, categories, store)
 	// Override start time if provided and not editing
 	if defaultTime != "" && event == nil && !m.allDay {
 		m.inputs[inputStartTime].SetValue(defaultTime)
@@ -63,12 +80,17 @@ func NewEventModalWithTime(date time.Time, event *model.Event, defaultTime strin
 	return m
 }
 
-func NewEventModal(date time.Time, event *model.Event, styles *Styles, categories []config.Category) *EventModal {
+func NewEventModal(date time.Time, event *model.Event, styles *Styles, categories []config.Category, store storage.Store) *EventModal {
+	if event != nil {
+		// Events are edited in their own time zone
+		event, date = event.FromDisplay(date)
+	}
 	m := &EventModal{
 		date:         date,
 		editingEvent: event,
 		styles:       styles,
-		inputs:       make([]textinput.Model, 4), // Reduced from 5 to 4 (removed category input)
+		store:        store,
+		inputs:       make([]textinput.Model, 9),
 		categories:   categories,
 		focusedField: FieldTitle, // Start with title focused
 	}
@@ -91,31 +113,75 @@ func NewEventModal(date time.Time, event *model.Event, styles *Styles, categorie
 	m.inputs[inputEndTime].Placeholder = "10:00 (optional)"
 	m.inputs[inputEndTime].CharLimit = 5
 	
-	// Description input
-	m.inputs[inputDescription].Placeholder = "Event description (optional)"
-	m.inputs[inputDescription].CharLimit = 200
+	// Description: free text over several lines
+	m.description = textarea.New()
+	m.description.Placeholder = "Event description (optional)"
+	m.description.ShowLineNumbers = false
+	m.description.CharLimit = 2000
+	m.description.SetWidth(60)
+	m.description.SetHeight(4)
+	
+	// End date input, for events spanning several days
+	m.inputs[inputEndDate].Placeholder = "YYYY-MM-DD (optional)"
+	m.inputs[inputEndDate].CharLimit = 10
+	
+	// Repeat input: a shorthand like "weekly" or an RRULE
+	m.inputs[inputRepeat].Placeholder = "daily, weekdays, weekly, monthly, yearly or RRULE (optional)"
+	m.inputs[inputRepeat].CharLimit = 100
+	
+	// Time zone input: an IANA name, or empty for floating times
+	m.inputs[inputTimeZone].Placeholder = "Europe/London (optional)"
+	m.inputs[inputTimeZone].CharLimit = 50
+	
+	// Location, meeting link and attendees
+	m.inputs[inputLocation].Placeholder = "Room, address or place (optional)"
+	m.inputs[inputLocation].CharLimit = 200
+	m.inputs[inputURL].Placeholder = "https://... (optional)"
+	m.inputs[inputURL].CharLimit = 500
+	m.inputs[inputAttendees].Placeholder = "Comma-separated names or emails (optional)"
+	m.inputs[inputAttendees].CharLimit = 500
 	
 	// Pre-fill if editing
 	if event != nil {
 		m.inputs[inputTitle].SetValue(event.Title)
+		if event.IsMultiDay() {
+			// Multi-day events are edited from their first day
+			if start, _, err := event.Dates(); err == nil {
+				m.date = start
+			}
+			// Overnight events get their end date from the times
+			if !event.IsOvernight() {
+				m.inputs[inputEndDate].SetValue(event.EndDate)
+			}
+		}
+		m.inputs[inputRepeat].SetValue(formatRepeat(event.Recurrence))
 		if !event.IsAllDay() {
 			m.inputs[inputStartTime].SetValue(event.StartTime)
 			m.inputs[inputEndTime].SetValue(event.EndTime)
+			m.inputs[inputTimeZone].SetValue(event.TimeZone)
 		} else {
 			m.allDay = true
 		}
-		// Find the category index
+		// Keep the event's categories and start the cursor on the primary one
+		m.chosenCats = append([]string(nil), event.Categories...)
 		for i, cat := range m.categories {
-			if cat.Name == event.Category {
+			if cat.Name == event.PrimaryCategory() {
 				m.selectedCatIdx = i
 				break
 			}
 		}
-		m.inputs[inputDescription].SetValue(event.Description)
+		m.description.SetValue(event.Description)
+		m.inputs[inputLocation].SetValue(event.Location)
+		m.inputs[inputURL].SetValue(event.URL)
+		m.inputs[inputAttendees].SetValue(strings.Join(event.Attendees, ", "))
 	} else {
 		// Default to 09:00-10:00 for new events
 		m.inputs[inputStartTime].SetValue("09:00")
 		m.inputs[inputEndTime].SetValue("10:00")
+		// and the first category
+		if len(m.categories) > 0 {
+			m.chosenCats = []string{m.categories[0].Name}
+		}
 	}
 	
 	return m
@@ -132,6 +198,21 @@ func (m *EventModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		
 	case tea.KeyMsg:
+		if m.scopeMode {
+			return m.updateScope(msg)
+		}
+		
+		// The description takes every key but those that leave or save it
+		if m.focusedField == FieldDescription && !m.categoryMode {
+			switch msg.String() {
+			case "tab", "shift+tab", "esc", "ctrl+c", "ctrl+s":
+			default:
+				var cmd tea.Cmd
+				m.description, cmd = m.description.Update(msg)
+				return m, cmd
+			}
+		}
+		
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			if m.categoryMode {
@@ -140,12 +221,25 @@ func (m *EventModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, func() tea.Msg { return ModalCloseMsg(true) }
 			
-		case "tab", "down", "j":
+		case "tab", "down":
 			return m.handleNavigation(1), nil
 			
-		case "shift+tab", "up", "k":
+		case "shift+tab", "up":
 			return m.handleNavigation(-1), nil
 			
+		case "j", "k":
+			// Vim keys move between fields unless they're being typed
+			if m.categoryMode || m.getInputIndex() == -1 {
+				if msg.String() == "j" {
+					return m.handleNavigation(1), nil
+				}
+				return m.handleNavigation(-1), nil
+			}
+			inputIdx := m.getInputIndex()
+			var cmd tea.Cmd
+			m.inputs[inputIdx], cmd = m.inputs[inputIdx].Update(msg)
+			return m, cmd
+			
 		case "ctrl+s", "enter":
 			newModel, cmd := m.handleAction()
 			return newModel, cmd
@@ -156,7 +250,11 @@ func (m *EventModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.toggleAllDay()
 				return m, nil
 			} else if m.focusedField == FieldCategory {
-				m.categoryMode = !m.categoryMode
+				if m.categoryMode {
+					m.toggleCategory()
+				} else {
+					m.categoryMode = true
+				}
 				return m, nil
 			}
 			// Fall through to default for text inputs
@@ -165,7 +263,10 @@ func (m *EventModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		default:
 			// Handle text input
 			if m.focusedField == FieldTitle || m.focusedField == FieldStartTime || 
-			   m.focusedField == FieldEndTime || m.focusedField == FieldDescription {
+			   m.focusedField == FieldEndTime || m.focusedField == FieldEndDate ||
+			   m.focusedField == FieldRepeat || m.focusedField == FieldTimeZone ||
+			   m.focusedField == FieldLocation || m.focusedField == FieldURL ||
+			   m.focusedField == FieldAttendees {
 				inputIdx := m.getInputIndex()
 				if inputIdx >= 0 && inputIdx < len(m.inputs) {
 					var cmd tea.Cmd
@@ -191,9 +292,9 @@ func (m *EventModal) handleNavigation(direction int) *EventModal {
 		return m
 	}
 	
-	fields := []FieldType{FieldTitle, FieldAllDay, FieldStartTime, FieldEndTime, FieldCategory, FieldDescription}
+	fields := []FieldType{FieldTitle, FieldAllDay, FieldStartTime, FieldEndTime, FieldTimeZone, FieldEndDate, FieldRepeat, FieldLocation, FieldURL, FieldAttendees, FieldCategory, FieldDescription}
 	if m.allDay {
-		fields = []FieldType{FieldTitle, FieldAllDay, FieldCategory, FieldDescription}
+		fields = []FieldType{FieldTitle, FieldAllDay, FieldEndDate, FieldRepeat, FieldLocation, FieldURL, FieldAttendees, FieldCategory, FieldDescription}
 	}
 	
 	currentIdx := -1
@@ -218,6 +319,10 @@ func (m *EventModal) handleNavigation(direction int) *EventModal {
 
 func (m *EventModal) handleAction() (*EventModal, tea.Cmd) {
 	if m.categoryMode {
+		// Enter picks the highlighted category if none is chosen yet
+		if len(m.chosenCats) == 0 {
+			m.toggleCategory()
+		}
 		m.categoryMode = false
 		return m, nil
 	}
@@ -227,21 +332,80 @@ func (m *EventModal) handleAction() (*EventModal, tea.Cmd) {
 		return m, nil
 	}
 	
+	// Edits to a recurring event first ask which occurrences they apply to
+	if m.editingEvent != nil && m.editingEvent.InSeries() && !m.scopeMode {
+		if _, err := m.buildEvent(); err != nil {
+			m.errorMsg = err.Error()
+			return m, nil
+		}
+		m.errorMsg = ""
+		m.scopeMode = true
+		return m, nil
+	}
+	
 	// Save event
 	if err := m.saveEvent(); err == nil {
 		return m, func() tea.Msg { return ModalCloseMsg(true) }
 	} else {
+		m.scopeMode = false
 		m.errorMsg = err.Error()
 		return m, nil
 	}
 }
 
+// updateScope handles keys while the scope prompt is shown
+func (m *EventModal) updateScope(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.scopeMode = false
+	case "down", "j", "tab":
+		m.scopeIdx = (m.scopeIdx + 1) % len(seriesScopes)
+	case "up", "k", "shift+tab":
+		m.scopeIdx = (m.scopeIdx + len(seriesScopes) - 1) % len(seriesScopes)
+	case "enter", "ctrl+s":
+		return m.handleAction()
+	default:
+		if idx := scopeForKey(msg.String()); idx >= 0 {
+			m.scopeIdx = idx
+			return m.handleAction()
+		}
+	}
+	return m, nil
+}
+
+// toggleCategory adds the highlighted category to the event, or removes it
+func (m *EventModal) toggleCategory() {
+	if m.selectedCatIdx >= len(m.categories) {
+		return
+	}
+	name := m.categories[m.selectedCatIdx].Name
+	for i, chosen := range m.chosenCats {
+		if chosen == name {
+			m.chosenCats = append(m.chosenCats[:i], m.chosenCats[i+1:]...)
+			return
+		}
+	}
+	m.chosenCats = append(m.chosenCats, name)
+}
+
+// isChosen reports whether a category is selected, and whether it is the
+// primary one
+func (m *EventModal) isChosen(name string) (chosen, primary bool) {
+	for i, c := range m.chosenCats {
+		if c == name {
+			return true, i == 0
+		}
+	}
+	return false, false
+}
+
 func (m *EventModal) toggleAllDay() {
 	m.allDay = !m.allDay
 	if m.allDay {
 		m.inputs[inputStartTime].SetValue("")
 		m.inputs[inputEndTime].SetValue("")
-		if m.focusedField == FieldStartTime || m.focusedField == FieldEndTime {
+		m.inputs[inputTimeZone].SetValue("")
+		if m.focusedField == FieldStartTime || m.focusedField == FieldEndTime || m.focusedField == FieldTimeZone {
 			m.focusedField = FieldCategory
 		}
 	} else {
@@ -259,8 +423,18 @@ func (m *EventModal) getInputIndex() int {
 		return inputStartTime
 	case FieldEndTime:
 		return inputEndTime
-	case FieldDescription:
-		return inputDescription
+	case FieldEndDate:
+		return inputEndDate
+	case FieldRepeat:
+		return inputRepeat
+	case FieldTimeZone:
+		return inputTimeZone
+	case FieldLocation:
+		return inputLocation
+	case FieldURL:
+		return inputURL
+	case FieldAttendees:
+		return inputAttendees
 	}
 	return -1
 }
@@ -269,6 +443,10 @@ func (m *EventModal) updateFocus() {
 	for i := range m.inputs {
 		m.inputs[i].Blur()
 	}
+	m.description.Blur()
+	if m.focusedField == FieldDescription {
+		m.description.Focus()
+	}
 	
 	inputIdx := m.getInputIndex()
 	if inputIdx >= 0 && inputIdx < len(m.inputs) {
@@ -277,21 +455,46 @@ func (m *EventModal) updateFocus() {
 }
 
 func (m *EventModal) saveEvent() error {
-	title := strings.TrimSpace(m.inputs[inputTitle].Value())
-	if title == "" {
-		return fmt.Errorf("title cannot be empty")
+	event, err := m.buildEvent()
+	if err != nil {
+		return err
 	}
 	
-	// Get selected category name
-	categoryName := ""
-	if m.selectedCatIdx < len(m.categories) {
-		categoryName = m.categories[m.selectedCatIdx].Name
+	if m.editingEvent != nil && m.editingEvent.InSeries() {
+		// Update one, some or all occurrences of a recurring event
+		return storage.UpdateOccurrence(m.store, m.date, m.editingEvent, event, seriesScopes[m.scopeIdx])
+	} else if m.editingEvent != nil {
+		// Update existing event using storage layer
+		return m.store.UpdateEvent(m.date, m.editingEvent, event)
+	} else {
+		// Add new event
+		return m.store.SaveEvent(m.date, event)
+	}
+}
+
+// buildEvent validates the form and returns the event it describes
+func (m *EventModal) buildEvent() (*model.Event, error) {
+	title := strings.TrimSpace(m.inputs[inputTitle].Value())
+	if title == "" {
+		return nil, fmt.Errorf("title cannot be empty")
 	}
 	
 	event := &model.Event{
 		Title:       title,
-		Category:    categoryName,
-		Description: strings.TrimSpace(m.inputs[inputDescription].Value()),
+		Categories:  append([]string(nil), m.chosenCats...),
+		Description: strings.TrimSpace(m.description.Value()),
+		Location:    strings.TrimSpace(m.inputs[inputLocation].Value()),
+		URL:         strings.TrimSpace(m.inputs[inputURL].Value()),
+		Attendees:   model.ParseCategories(m.inputs[inputAttendees].Value()),
+	}
+	if event.URL != "" && !isValidURL(event.URL) {
+		return nil, fmt.Errorf("invalid URL (use http://, https:// or mailto:)")
+	}
+	
+	// Keep the fields the form doesn't edit
+	if m.editingEvent != nil {
+		event.Status = m.editingEvent.Status
+		event.Extra = append([]model.Property(nil), m.editingEvent.Extra...)
 	}
 	
 	if m.allDay {
@@ -301,24 +504,59 @@ func (m *EventModal) saveEvent() error {
 		event.StartTime = strings.TrimSpace(m.inputs[inputStartTime].Value())
 		event.EndTime = strings.TrimSpace(m.inputs[inputEndTime].Value())
 		if event.StartTime == "" {
-			return fmt.Errorf("start time required for timed events")
+			return nil, fmt.Errorf("start time required for timed events")
 		}
 		// Validate time format
 		if !isValidTime(event.StartTime) {
-			return fmt.Errorf("invalid start time format (use HH:MM)")
+			return nil, fmt.Errorf("invalid start time format (use HH:MM)")
 		}
 		if event.EndTime != "" && !isValidTime(event.EndTime) {
-			return fmt.Errorf("invalid end time format (use HH:MM)")
+			return nil, fmt.Errorf("invalid end time format (use HH:MM)")
+		}
+		if event.EndTime == event.StartTime {
+			return nil, fmt.Errorf("end time must differ from start time")
+		}
+		event.TimeZone = strings.TrimSpace(m.inputs[inputTimeZone].Value())
+		if _, err := event.Zone(); err != nil {
+			return nil, err
 		}
 	}
 	
-	if m.editingEvent != nil {
-		// Update existing event using storage layer
-		return storage.UpdateEvent(m.date, m.editingEvent, event)
-	} else {
-		// Add new event
-		return storage.SaveEvent(m.date, event)
+	// An end date after the start date makes this a multi-day event
+	if endDate := strings.TrimSpace(m.inputs[inputEndDate].Value()); endDate != "" {
+		end, err := time.ParseInLocation(model.DateFormat, endDate, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end date format (use YYYY-MM-DD)")
+		}
+		start := time.Date(m.date.Year(), m.date.Month(), m.date.Day(), 0, 0, 0, 0, time.Local)
+		if end.Before(start) {
+			return nil, fmt.Errorf("end date is before the start date")
+		}
+		if end.After(start) {
+			if !m.allDay && event.EndTime == "" {
+				return nil, fmt.Errorf("end time required for multi-day events")
+			}
+			event.StartDate = start.Format(model.DateFormat)
+			event.EndDate = end.Format(model.DateFormat)
+		} else if event.IsOvernight() {
+			return nil, fmt.Errorf("end time is before start time on the same day")
+		}
+	} else if event.IsOvernight() {
+		// An end time before the start time means the event ends the next day
+		event.SpanOvernight(m.date)
+	}
+	
+	// Recurring series start on the modal's date
+	rule, err := parseRepeat(m.inputs[inputRepeat].Value())
+	if err != nil {
+		return nil, err
 	}
+	if rule != nil {
+		event.Recurrence = rule
+		event.StartDate = m.date.Format(model.DateFormat)
+	}
+	
+	return event, nil
 }
 
 func (m *EventModal) View() string {
@@ -358,8 +596,30 @@ func (m *EventModal) View() string {
 	// Time fields (only if not all-day)
 	if !m.allDay {
 		content = append(content, m.renderField("🕒 Start Time", FieldStartTime, m.inputs[inputStartTime].View()))
-		content = append(content, m.renderField("🕕 End Time", FieldEndTime, m.inputs[inputEndTime].View()))
+		endTimeView := m.inputs[inputEndTime].View()
+		if m.endsNextDay() {
+			endTimeView = lipgloss.JoinVertical(lipgloss.Left, endTimeView,
+				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Ends the next day"))
+		}
+		content = append(content, m.renderField("🕕 End Time", FieldEndTime, endTimeView))
+		
+		// Time zone, with the start time in the display zone below it
+		zoneView := m.inputs[inputTimeZone].View()
+		if hint := m.displayTimeHint(); hint != "" {
+			zoneView = lipgloss.JoinVertical(lipgloss.Left, zoneView,
+				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(hint))
+		}
+		content = append(content, m.renderField("🌐 Time Zone", FieldTimeZone, zoneView))
+	}
+	content = append(content, m.renderField("📅 End Date", FieldEndDate, m.inputs[inputEndDate].View()))
+	
+	// Repeat field, with the parsed rule spelled out below it
+	repeatView := m.inputs[inputRepeat].View()
+	if rule, err := parseRepeat(m.inputs[inputRepeat].Value()); err == nil && rule != nil {
+		repeatView = lipgloss.JoinVertical(lipgloss.Left, repeatView,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(rule.Describe()))
 	}
+	content = append(content, m.renderField("🔁 Repeat", FieldRepeat, repeatView))
 	
 	// Category selector
 	if m.categoryMode {
@@ -368,8 +628,18 @@ func (m *EventModal) View() string {
 		content = append(content, m.renderField("🏷️ Category", FieldCategory, m.renderSelectedCategory()))
 	}
 	
+	// Where, link and who
+	content = append(content, m.renderField("📍 Location", FieldLocation, m.inputs[inputLocation].View()))
+	content = append(content, m.renderField("🔗 URL", FieldURL, m.inputs[inputURL].View()))
+	content = append(content, m.renderField("👥 Attendees", FieldAttendees, m.inputs[inputAttendees].View()))
+	
+	// Details from the event file that the form doesn't edit
+	if details := m.renderDetails(); details != "" {
+		content = append(content, details)
+	}
+	
 	// Description field
-	content = append(content, m.renderField("📄 Description", FieldDescription, m.inputs[inputDescription].View()))
+	content = append(content, m.renderField("📄 Description", FieldDescription, m.description.View()))
 	
 	// Error message
 	if m.errorMsg != "" {
@@ -383,8 +653,11 @@ func (m *EventModal) View() string {
 		content = append(content, errorBox)
 	}
 	
-	// Instructions
+	// Instructions, or the scope prompt for recurring events
 	instructions := m.renderInstructions()
+	if m.scopeMode {
+		instructions = renderScopePrompt("Apply changes to:", m.scopeIdx)
+	}
 	
 	// Combine all content
 	mainContent := lipgloss.JoinVertical(lipgloss.Left, content...)
@@ -406,6 +679,38 @@ func (m *EventModal) View() string {
 		modal)
 }
 
+// endsNextDay reports whether the entered times describe an overnight
+// event without an explicit end date
+func (m *EventModal) endsNextDay() bool {
+	start := strings.TrimSpace(m.inputs[inputStartTime].Value())
+	end := strings.TrimSpace(m.inputs[inputEndTime].Value())
+	if m.allDay || !isValidTime(start) || !isValidTime(end) {
+		return false
+	}
+	// An end of 00:00 is midnight at the end of the day
+	return strings.TrimSpace(m.inputs[inputEndDate].Value()) == "" && end < start && end != "00:00"
+}
+
+// displayTimeHint describes when the entered start time is in the display
+// zone, if the event is in another zone
+func (m *EventModal) displayTimeHint() string {
+	z, ok := m.store.(interface{ Location() *time.Location })
+	start := strings.TrimSpace(m.inputs[inputStartTime].Value())
+	if !ok || !isValidTime(start) {
+		return ""
+	}
+	event := &model.Event{StartTime: start, TimeZone: strings.TrimSpace(m.inputs[inputTimeZone].Value())}
+	shown := event.InZone(m.date, z.Location())
+	if shown.DisplayZone == "" {
+		return ""
+	}
+	hint := fmt.Sprintf("Starts %s %s", shown.StartTime, shown.DisplayZone)
+	if shown.StartDate != m.date.Format(model.DateFormat) {
+		hint += " on " + shown.StartDate
+	}
+	return hint
+}
+
 // Helper methods for rendering UI components
 func (m *EventModal) renderField(label string, fieldType FieldType, content string) string {
 	isFocused := m.focusedField == fieldType && !m.categoryMode
@@ -436,27 +741,68 @@ func (m *EventModal) renderField(label string, fieldType FieldType, content stri
 	return fieldStyle.Render(field)
 }
 
+// renderDetails lists the edited event's status and custom header keys
+func (m *EventModal) renderDetails() string {
+	evt := m.editingEvent
+	if evt == nil {
+		return ""
+	}
+	var lines []string
+	if evt.Status != "" {
+		lines = append(lines, "Status: "+evt.Status)
+	}
+	for _, p := range evt.Extra {
+		lines = append(lines, fmt.Sprintf("%s: %s", p.Key, p.Value))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	
+	label := lipgloss.NewStyle().Foreground(lipgloss.Color("247")).Render("ℹ️ Details")
+	body := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render(strings.Join(lines, "\n"))
+	return lipgloss.NewStyle().Margin(0, 0, 1, 0).Render(lipgloss.JoinVertical(lipgloss.Left, label, body))
+}
+
 func (m *EventModal) renderSelectedCategory() string {
-	if m.selectedCatIdx >= len(m.categories) {
+	if len(m.chosenCats) == 0 {
 		return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("(none)")
 	}
 	
-	cat := m.categories[m.selectedCatIdx]
-	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color(cat.Color)).
-		Render(fmt.Sprintf("● %s", cat.Name))
+	var chosen []string
+	for _, name := range m.chosenCats {
+		color := "#808080"
+		for _, cat := range m.categories {
+			if cat.Name == name {
+				color = cat.Color
+			}
+		}
+		chosen = append(chosen, lipgloss.NewStyle().
+			Foreground(lipgloss.Color(color)).
+			Render(fmt.Sprintf("● %s", name)))
+	}
+	return strings.Join(chosen, "  ")
 }
 
 func (m *EventModal) renderCategorySelector() string {
 	header := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("39")).
 		Bold(true).
-		Render("🏷️ Select Category:")
+		Render("🏷️ Select Categories:")
 	
 	var categories []string
 	for i, cat := range m.categories {
+		check := "☐"
+		chosen, primary := m.isChosen(cat.Name)
+		if chosen {
+			check = "☑"
+		}
+		name := cat.Name
+		if primary && len(m.chosenCats) > 1 {
+			name += " (primary)"
+		}
+		
 		catStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(cat.Color))
-		catText := fmt.Sprintf("● %s", cat.Name)
+		catText := fmt.Sprintf("%s ● %s", check, name)
 		
 		if i == m.selectedCatIdx {
 			catText = lipgloss.NewStyle().
@@ -464,7 +810,7 @@ func (m *EventModal) renderCategorySelector() string {
 				Foreground(lipgloss.Color("0")).
 				Padding(0, 1).
 				Bold(true).
-				Render("▶ " + cat.Name + " ◀")
+				Render("▶ " + check + " " + name + " ◀")
 		} else {
 			catText = "  " + catStyle.Render(catText)
 		}
@@ -493,8 +839,15 @@ func (m *EventModal) renderInstructions() string {
 	if m.categoryMode {
 		instructions = append(instructions,
 			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("↑↓ Navigate categories"),
-			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Enter/Space Select"),
-			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Esc Cancel"),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Space Toggle"),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Enter/Esc Done"),
+		)
+	} else if m.focusedField == FieldDescription {
+		instructions = append(instructions,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Tab Navigate fields"),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Enter New line"),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("33")).Bold(true).Render("Ctrl+S Save event"),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("Esc Cancel"),
 		)
 	} else {
 		instructions = append(instructions,
@@ -522,14 +875,18 @@ type DeleteModal struct {
 	width    int
 	height   int
 	confirmed bool
+	scopeIdx int // for recurring events: which occurrences to delete
+	store    storage.Store
+	errorMsg string // why the last delete failed
 }
 
-func NewDeleteModal(date time.Time, event *model.Event, index int, styles *Styles) *DeleteModal {
+func NewDeleteModal(date time.Time, event *model.Event, index int, styles *Styles, store storage.Store) *DeleteModal {
 	return &DeleteModal{
 		date:      date,
 		event:     event,
 		index:     index,
 		styles:    styles,
+		store:     store,
 		confirmed: false,
 	}
 }
@@ -552,19 +909,45 @@ func (m *DeleteModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			
 		case "y", "Y", "enter":
 			// Confirm deletion
-			if err := m.deleteEvent(); err == nil {
-				m.confirmed = true
+			return m.confirm()
+		}
+		
+		// Recurring events: pick the scope, or delete right away with its key
+		if m.event.InSeries() {
+			switch key := msg.String(); key {
+			case "down", "j", "tab":
+				m.scopeIdx = (m.scopeIdx + 1) % len(seriesScopes)
+			case "up", "k", "shift+tab":
+				m.scopeIdx = (m.scopeIdx + len(seriesScopes) - 1) % len(seriesScopes)
+			default:
+				if idx := scopeForKey(key); idx >= 0 {
+					m.scopeIdx = idx
+					return m.confirm()
+				}
 			}
-			return m, func() tea.Msg { return ModalCloseMsg(true) }
 		}
 	}
 	
 	return m, nil
 }
 
+// confirm deletes the event and closes the modal, or keeps it open with
+// the error if the delete fails
+func (m *DeleteModal) confirm() (tea.Model, tea.Cmd) {
+	if err := m.deleteEvent(); err != nil {
+		m.errorMsg = err.Error()
+		return m, nil
+	}
+	m.confirmed = true
+	return m, func() tea.Msg { return ModalCloseMsg(true) }
+}
+
 func (m *DeleteModal) deleteEvent() error {
+	if m.event.InSeries() {
+		return storage.DeleteOccurrence(m.store, m.date, m.event, seriesScopes[m.scopeIdx])
+	}
 	// Use storage layer's delete function
-	return storage.DeleteEvent(m.date, m.event)
+	return m.store.DeleteEvent(m.date, m.event)
 }
 
 func (m *DeleteModal) View() string {
@@ -584,8 +967,18 @@ func (m *DeleteModal) View() string {
 		eventTitle = fmt.Sprintf("🕐 %s (%s)", eventTitle, timeStr)
 	}
 	
-	if m.event.Category != "" {
-		eventTitle = fmt.Sprintf("%s\n   Category: %s", eventTitle, m.event.Category)
+	if m.event.IsMultiDay() {
+		eventTitle = fmt.Sprintf("%s\n   %s → %s", eventTitle, m.event.StartDate, m.event.EndDate)
+	}
+	
+	if m.event.IsRecurring() {
+		eventTitle = fmt.Sprintf("%s\n   %s%s", eventTitle, recurringMarker, m.event.Recurrence.Describe())
+	} else if m.event.IsOverride() {
+		eventTitle = fmt.Sprintf("%s\n   %sModified occurrence", eventTitle, recurringMarker)
+	}
+	
+	if len(m.event.Categories) > 0 {
+		eventTitle = fmt.Sprintf("%s\n   Category: %s", eventTitle, strings.Join(m.event.Categories, ", "))
 	}
 	
 	// Build the modal content
@@ -613,16 +1006,23 @@ func (m *DeleteModal) View() string {
 		Foreground(lipgloss.Color("240")).
 		Render("[Y]es / [N]o")
 	
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		header,
-		"",
-		dateStr,
-		eventBox,
-		"",
-		question,
-		"",
-		instructions,
-	)
+	if m.event.InSeries() {
+		question = renderScopePrompt("Delete which events?", m.scopeIdx)
+	}
+	
+	lines := []string{header, "", dateStr, eventBox, "", question, "", instructions}
+	if m.errorMsg != "" {
+		errorBox := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Background(lipgloss.Color("52")).
+			Padding(0, 1).
+			Margin(1, 0).
+			Bold(true).
+			Width(52).
+			Render("❌ " + m.errorMsg)
+		lines = append(lines, errorBox)
+	}
+	content := lipgloss.JoinVertical(lipgloss.Center, lines...)
 	
 	modalStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -711,6 +1111,8 @@ func (m *HelpModal) View() string {
 		helpText = append(helpText, "  h/l ←/→   Previous/next day")
 		helpText = append(helpText, "  j/k ↑/↓   Move between hours")
 		helpText = append(helpText, "  Ctrl+U/D  Previous/next day")
+	case ListView:
+		helpText = append(helpText, "  n         Toggle day note excerpts")
 	}
 	helpText = append(helpText, "  ↑/↓       Navigate agenda")
 	helpText = append(helpText, "  hjkl      Navigate calendar")
@@ -725,6 +1127,17 @@ func (m *HelpModal) View() string {
 	helpText = append(helpText, "  d         Delete selected event (agenda/list)")
 	helpText = append(helpText, "  y         Yank (copy) selected event")
 	helpText = append(helpText, "  p         Paste yanked event")
+	helpText = append(helpText, "  u         Undo the last change to events")
+	helpText = append(helpText, "  Ctrl+R    Redo the last undone change")
+	helpText = append(helpText, "  D         Recently deleted events")
+	helpText = append(helpText, "  H         History of the selected event (git history)")
+	helpText = append(helpText, "  c         Filter by category (cycles)")
+	helpText = append(helpText, "  /         Search events")
+	helpText = append(helpText, "  o / O     Open / copy the selected event's link")
+	helpText = append(helpText, "  x         Cycle status (tentative, confirmed, cancelled)")
+	helpText = append(helpText, "  T         Add a task")
+	helpText = append(helpText, "  Space     Tick the selected task off")
+	helpText = append(helpText, "  N         View / edit the day's note")
 	helpText = append(helpText, "")
 	
 	helpText = append(helpText, lipgloss.NewStyle().Bold(true).Render("General:"))
@@ -774,10 +1187,7 @@ func calculateEndTime(startTime string) string {
 	}
 	var hour, min int
 	fmt.Sscanf(startTime, "%d:%d", &hour, &min)
-	hour++
-	if hour > 23 {
-		hour = 23 // Cap at 23:00
-	}
+	hour = (hour + 1) % 24 // Late starts end after midnight
 	return fmt.Sprintf("%02d:%02d", hour, min)
 }
 
@@ -857,12 +1267,20 @@ func (m *SettingsModal) View() string {
 	
 	// Layout settings
 	settings = append(settings, fmt.Sprintf("Mini Month in Week View: %v", m.config.ShowMiniMonth))
+	settings = append(settings, fmt.Sprintf("Note Excerpts in List View: %v", m.config.ListNotes))
 	settings = append(settings, fmt.Sprintf("Agenda Position: %s", func() string {
 		if m.config.AgendaBottom {
 			return "Bottom"
 		}
 		return "Right"
 	}()))
+	settings = append(settings, fmt.Sprintf("Deleted Events Kept: %s", func() string {
+		if retention := m.config.TrashRetention(); retention > 0 {
+			return fmt.Sprintf("%d days", int(retention.Hours()/24))
+		}
+		return "Until purged"
+	}()))
+	settings = append(settings, fmt.Sprintf("Git History: %v", m.config.GitHistory))
 	settings = append(settings, "")
 	
 	// Categories
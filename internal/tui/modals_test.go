@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refusingStore refuses every delete as another program's change would
+type refusingStore struct {
+	storage.Store
+}
+
+func (refusingStore) DeleteEvent(time.Time, *model.Event) error {
+	return storage.ErrConflict
+}
+
+func TestDeleteModalShowsConflict(t *testing.T) {
+	date := time.Date(2025, 8, 13, 0, 0, 0, 0, time.Local)
+	event := &model.Event{ID: "1", StartTime: "09:00", Title: "Standup"}
+	modal := NewDeleteModal(date, event, 0, DefaultStyles(), refusingStore{storage.NewMemoryStore()})
+	modal.width, modal.height = 100, 40
+
+	_, cmd := modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd != nil {
+		t.Fatal("the modal closed although the delete was refused")
+	}
+	if modal.confirmed {
+		t.Error("the delete counts as confirmed")
+	}
+	if view := modal.View(); !strings.Contains(view, "not saved") {
+		t.Errorf("the modal doesn't show the conflict:\n%s", view)
+	}
+}
+
+func TestDeleteTaskAsks(t *testing.T) {
+	store := storage.NewMemoryStore()
+	task := &model.Task{Title: "Send report", Due: "2025-08-20"}
+	if err := store.SaveTask(task); err != nil {
+		t.Fatal(err)
+	}
+	modal := NewDeleteTaskModal(task, DefaultStyles(), store)
+	modal.width, modal.height = 100, 40
+	if view := modal.View(); !strings.Contains(view, "Delete this task?") {
+		t.Errorf("the modal doesn't ask:\n%s", view)
+	}
+
+	modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if tasks, _ := store.LoadTasks(); len(tasks) != 1 {
+		t.Fatal("deleted the task although the delete was cancelled")
+	}
+	if _, cmd := modal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}); cmd == nil {
+		t.Error("the modal stays open after deleting")
+	}
+	if tasks, _ := store.LoadTasks(); len(tasks) != 0 {
+		t.Error("the task is still there")
+	}
+}
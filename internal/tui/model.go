@@ -1,10 +1,12 @@
 package tui
 
 import (
+	"context"
 	"bubblecal/internal/config"
 	"bubblecal/internal/model"
 	"bubblecal/internal/storage"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -31,6 +33,14 @@ type Model struct {
 	
 	// Data
 	events       []*model.Event // Events for selected date
+	data         calendarData   // What the views draw from, see loadData
+	loadSeq      int            // Identifies the latest load, to drop stale results
+	cancelLoad   context.CancelFunc
+	loading      bool           // A load of loadFrom..loadTo is running
+	loadFrom     time.Time
+	loadTo       time.Time
+	stale        bool           // The store changed since m.data was loaded
+	watcher      *watcher       // Notices files changed by other programs
 	
 	// UI state
 	width        int
@@ -61,9 +71,21 @@ type Model struct {
 	// Yanked (copied) event
 	yankedEvent  *model.Event
 	
+	// One-line feedback shown in the header until the next key
+	statusMsg    string
+	
 	// Config
 	config       *config.Config
 	
+	// Event storage
+	store        storage.Store
+	filter       *categoryFilter // wraps store, hiding events outside a category
+	cache        *storage.CachedStore
+	undoLog      *storage.UndoLog // records writes for u and ctrl+r
+	trash        storage.TrashStore // deleted events, nil if the store keeps none
+	git          *storage.GitStore  // commits every change, nil unless git history is on
+	tasks        []*model.Task   // Tasks listed on the selected date
+	
 	// Styling
 	styles       *Styles
 }
@@ -187,12 +209,44 @@ func DefaultStyles() *Styles {
 	return GetStyles(ThemeDefault)
 }
 
-// NewModel creates a new application model
-func NewModel() *Model {
-	now := time.Now()
-	
+// NewModel creates a new application model backed by store
+func NewModel(store storage.Store) *Model {
 	// Load configuration
 	cfg, _ := config.Load()
+	now := currentTime(cfg)
+	
+	// Show events in the configured time zone, optionally filtered by
+	// category, redrawing from memory. Writes are logged so they can be
+	// undone, next to the calendar files if there are any.
+	cache := storage.NewCachedStore(store)
+	undoPath := ""
+	if r, ok := store.(interface{ Root() string }); ok {
+		undoPath = filepath.Join(r.Root(), "undo.json")
+	}
+	// The log holds whole events, so it isn't written for an encrypted
+	// calendar
+	if fs, ok := store.(*storage.FileStore); ok && fs.Encrypted() {
+		undoPath = ""
+	}
+	// With git history on, every change is also committed
+	var logged storage.Store = cache
+	var git *storage.GitStore
+	gitErr := ""
+	if fs, ok := store.(*storage.FileStore); ok && cfg.GitHistory {
+		if g, err := storage.NewGitStore(cache, fs); err != nil {
+			gitErr = "Git history is off: " + err.Error()
+		} else {
+			git = g
+			logged = git
+		}
+	}
+	undoLog := storage.NewUndoLog(logged, undoPath)
+	filter := &categoryFilter{Store: storage.NewZonedStore(undoLog, cfg.Location())}
+	filter.tasks, _ = store.(storage.TaskStore)
+	filter.notes = cache
+	watch := newWatcher(store)
+	trash, _ := store.(storage.TrashStore)
+	store = filter
 	
 	m := &Model{
 		selectedDate: now,
@@ -202,20 +256,25 @@ func NewModel() *Model {
 		agendaBottom:  cfg.AgendaBottom,
 		currentTheme:  ThemeType(cfg.Theme),
 		config:       cfg,
+		store:        store,
+		filter:       filter,
+		cache:        cache,
+		undoLog:      undoLog,
+		trash:        trash,
+		git:          git,
+		watcher:      watch,
+		statusMsg:    gitErr,
 		styles:       GetStyles(ThemeType(cfg.Theme)),
 	}
 	
 	// Initialize views
-	m.monthView = NewMonthViewModel(&m.selectedDate, m.styles, cfg)
-	m.weekView = NewWeekViewModel(&m.selectedDate, &m.selectedHour, m.styles, cfg)
+	m.monthView = NewMonthViewModel(&m.selectedDate, m.styles, cfg, &m.data)
+	m.weekView = NewWeekViewModel(&m.selectedDate, &m.selectedHour, m.styles, cfg, &m.data)
 	m.weekView.SetShowMiniMonth(m.showMiniMonth)
-	m.dayView = NewDayViewModel(&m.selectedDate, &m.selectedHour, m.styles, cfg)
-	m.listView = NewListViewModel(&m.selectedDate, m.styles, cfg)
+	m.dayView = NewDayViewModel(&m.selectedDate, &m.selectedHour, m.styles, cfg, &m.data)
+	m.listView = NewListViewModel(&m.selectedDate, m.styles, cfg, &m.data)
 	m.agendaView = NewAgendaViewModel(&m.selectedDate, m.styles, cfg)
 	
-	// Load initial events
-	m.loadEvents()
-	
 	return m
 }
 
@@ -223,14 +282,59 @@ func NewModel() *Model {
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
 		tea.EnterAltScreen,
-		loadEventsCmd(m.selectedDate),
+		m.loadData(),
+		watchCmd(m.watcher, m.data.from, m.data.to),
+		purgeTrashCmd(m.trash, m.config.TrashRetention()),
 	)
 }
 
-// Update handles messages
+// Update handles messages, then starts loading whatever the views now
+// need that isn't loaded yet. The writes made while handling a message
+// are undone together.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if err := m.undoLog.Seal(); err != nil {
+		m.statusMsg = err.Error()
+	}
+	return next, tea.Batch(cmd, m.loadData(), commitCmd(m.git))
+}
+
+// Close commits what is still waiting to be committed; call it once the
+// program has ended
+func (m *Model) Close() error {
+	if m.git == nil {
+		return nil
+	}
+	return m.git.Commit()
+}
+
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	
+	// Loads and file checks finish whether or not a modal is open
+	switch msg := msg.(type) {
+	case DataLoadedMsg:
+		if msg.seq == m.loadSeq {
+			// Keep the selected rows selected, wherever they move to
+			listKey, agendaKey := m.listView.selectedKey(), m.agendaView.selectedKey()
+			m.data = msg.data
+			m.loading = false
+			m.listView.LoadEvents()
+			m.listView.selectKey(listKey)
+			m.loadEvents()
+			m.agendaView.selectKey(agendaKey)
+		}
+		return m, nil
+		
+	case committedMsg:
+		m.statusMsg = msg.err.Error()
+		return m, nil
+		
+	case FilesChangedMsg:
+		m.applyFileChanges(msg)
+		return m, watchCmd(m.watcher, m.data.from, m.data.to)
+	}
+	
 	// Handle modal updates first
 	if len(m.modalStack) > 0 {
 		modal := m.modalStack[len(m.modalStack)-1]
@@ -241,8 +345,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if _, ok := msg.(ModalCloseMsg); ok {
 			m.modalStack = m.modalStack[:len(m.modalStack)-1]
 			// Reload events after modal closes
-			m.loadEvents()
-			cmds = append(cmds, loadEventsCmd(m.selectedDate))
+			m.stale = true
 		}
 		
 		if cmd != nil {
@@ -261,11 +364,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.updateViewSizes()
 		
-	case EventsLoadedMsg:
-		m.events = msg.Events
-		m.agendaView.SetEvents(m.events)
+	case JumpToDateMsg:
+		// A search result was picked
+		m.selectedDate = time.Time(msg)
 		
 	case tea.KeyMsg:
+		m.statusMsg = ""
+		
 		// Handle jump mode first
 		if m.jumpMode {
 			return m.handleJumpMode(msg.String()), nil
@@ -310,8 +415,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case MonthView:
 				m.currentView = WeekView
 				// Set selected hour to current hour if today, otherwise noon
-				if sameDay(m.selectedDate, time.Now()) {
-					hour := time.Now().Hour()
+				if sameDay(m.selectedDate, currentTime(m.config)) {
+					hour := currentTime(m.config).Hour()
 					if hour >= 8 && hour <= 20 {
 						m.selectedHour = hour
 					} else {
@@ -348,8 +453,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case ListView:
 				m.currentView = DayView
 				// Set selected hour for day view
-				if sameDay(m.selectedDate, time.Now()) {
-					hour := time.Now().Hour()
+				if sameDay(m.selectedDate, currentTime(m.config)) {
+					hour := currentTime(m.config).Hour()
 					if hour >= 6 && hour <= 22 {
 						m.selectedHour = hour
 					} else {
@@ -362,10 +467,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			
 		case "t", ".":
 			// Go to today
-			m.selectedDate = time.Now()
+			m.selectedDate = currentTime(m.config)
 			// Also set to current hour in week/day views
 			if m.currentView == WeekView {
-				hour := time.Now().Hour()
+				hour := currentTime(m.config).Hour()
 				if hour >= 8 && hour <= 20 {
 					m.selectedHour = hour
 				} else if hour < 8 {
@@ -374,7 +479,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedHour = 20
 				}
 			} else if m.currentView == DayView {
-				hour := time.Now().Hour()
+				hour := currentTime(m.config).Hour()
 				if hour >= 6 && hour <= 22 {
 					m.selectedHour = hour
 				} else if hour < 6 {
@@ -383,8 +488,62 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedHour = 22
 				}
 			}
-			m.loadEvents()
-			cmds = append(cmds, loadEventsCmd(m.selectedDate))
+			
+		case "u":
+			// Undo the last change
+			if desc, err := m.undoLog.Undo(); err != nil {
+				m.statusMsg = err.Error()
+			} else if desc == "" {
+				m.statusMsg = "Nothing to undo"
+			} else {
+				m.statusMsg = "Undid " + desc
+			}
+			m.stale = true
+			
+		case "ctrl+r":
+			// Redo the last undone change
+			if desc, err := m.undoLog.Redo(); err != nil {
+				m.statusMsg = err.Error()
+			} else if desc == "" {
+				m.statusMsg = "Nothing to redo"
+			} else {
+				m.statusMsg = "Redid " + desc
+			}
+			m.stale = true
+			
+		case "D":
+			// Show recently deleted events
+			if m.trash == nil {
+				m.statusMsg = "This calendar has no trash"
+				return m, nil
+			}
+			modal := NewTrashModal(m.trash, m.store, m.styles)
+			modal.width = m.width
+			modal.height = m.height
+			m.modalStack = append(m.modalStack, modal)
+			return m, modal.Init()
+			
+		case "H":
+			// Show the committed versions of the selected event
+			if m.git == nil {
+				m.statusMsg = "Set git_history in config.json to keep a history of events"
+				return m, nil
+			}
+			date := m.selectedDate
+			evt := m.selectedEvent()
+			if m.currentView == ListView {
+				if sel := m.listView.GetSelectedEvent(); sel != nil {
+					date = sel.Date
+				}
+			}
+			if evt == nil {
+				return m, nil
+			}
+			modal := NewHistoryModal(m.git, m.store, date, evt, m.styles)
+			modal.width = m.width
+			modal.height = m.height
+			m.modalStack = append(m.modalStack, modal)
+			return m, modal.Init()
 			
 		case "f":
 			// Enter calendar jump mode
@@ -404,13 +563,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case DayView:
 				defaultTime = m.dayView.GetSelectedHour()
 			}
-			modal := NewEventModalWithTime(m.selectedDate, nil, defaultTime, m.styles, m.config.Categories)
+			modal := NewEventModalWithTime(m.selectedDate, nil, defaultTime, m.styles, m.config.Categories, m.store)
 			// Set modal window size
 			modal.width = m.width
 			modal.height = m.height
 			m.modalStack = append(m.modalStack, modal)
 			return m, modal.Init()
 			
+		case "c":
+			// Cycle the category filter through the configured categories
+			m.filter.next(m.config.Categories)
+			m.stale = true
+			
 		case "m":
 			// Toggle mini-month view (only in week view)
 			if m.currentView == WeekView {
@@ -487,40 +651,62 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					StartTime:   m.yankedEvent.StartTime,
 					EndTime:     m.yankedEvent.EndTime,
 					Title:       m.yankedEvent.Title,
-					Category:    m.yankedEvent.Category,
+					Categories:  append([]string(nil), m.yankedEvent.Categories...),
 					Description: m.yankedEvent.Description,
+					Location:    m.yankedEvent.Location,
+					URL:         m.yankedEvent.URL,
+					Attendees:   append([]string(nil), m.yankedEvent.Attendees...),
+					Status:      m.yankedEvent.Status,
+					Extra:       append([]model.Property(nil), m.yankedEvent.Extra...),
+					TimeZone:    m.yankedEvent.TimeZone,
+					DisplayZone: m.yankedEvent.DisplayZone, // times are as shown, not as stored
+				}
+				
+				// Multi-day events keep their length and start on the selected date
+				if m.yankedEvent.IsMultiDay() {
+					newEvent.StartDate = m.selectedDate.Format(model.DateFormat)
+					newEvent.EndDate = m.selectedDate.AddDate(0, 0, m.yankedEvent.SpanDays()-1).Format(model.DateFormat)
 				}
 				
 				// If in week or day view and not an all-day event, update the time to selected hour
 				if !newEvent.IsAllDay() && (m.currentView == WeekView || m.currentView == DayView) {
-					// Calculate duration if there's an end time
+					// Calculate duration if there's an end time, counting
+					// the days an overnight or multi-day event runs into
 					var duration int
 					if newEvent.EndTime != "" {
 						var startHour, startMin, endHour, endMin int
 						fmt.Sscanf(newEvent.StartTime, "%d:%d", &startHour, &startMin)
 						fmt.Sscanf(newEvent.EndTime, "%d:%d", &endHour, &endMin)
 						duration = (endHour*60 + endMin) - (startHour*60 + startMin)
+						duration += (m.yankedEvent.SpanDays() - 1) * 24 * 60
+						if duration <= 0 && (m.yankedEvent.IsOvernight() || newEvent.EndTime == "00:00") {
+							duration += 24 * 60
+						}
 					}
 					
 					// Set new start time based on selected hour
 					newEvent.StartTime = fmt.Sprintf("%02d:00", m.selectedHour)
+					newEvent.StartDate = ""
+					newEvent.EndDate = ""
 					
-					// Set new end time if there was one
+					// Set new end time if there was one, possibly on a later day
 					if duration > 0 {
 						endMinutes := m.selectedHour*60 + duration
-						newEvent.EndTime = fmt.Sprintf("%02d:%02d", endMinutes/60, endMinutes%60)
+						newEvent.EndTime = fmt.Sprintf("%02d:%02d", (endMinutes/60)%24, endMinutes%60)
+						if days := endMinutes / (24 * 60); days > 1 || (days == 1 && endMinutes%(24*60) >= m.selectedHour*60) {
+							newEvent.StartDate = m.selectedDate.Format(model.DateFormat)
+							newEvent.EndDate = m.selectedDate.AddDate(0, 0, days).Format(model.DateFormat)
+						}
 					} else {
 						newEvent.EndTime = ""
 					}
 				}
 				
 				// Save the event to the selected date
-				err := storage.SaveEvent(m.selectedDate, newEvent)
-				if err == nil {
-					// Reload events after successful paste
-					m.loadEvents()
-					cmds = append(cmds, loadEventsCmd(m.selectedDate))
+				if err := m.store.SaveEvent(m.selectedDate, newEvent); err != nil {
+					m.statusMsg = err.Error()
 				}
+				m.stale = true
 			}
 			
 		case "?":
@@ -534,9 +720,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			
 		case "e":
 			// Edit selected event (works on agenda or list view)
+			if t := m.selectedTask(); t != nil {
+				modal := NewTaskModal(m.selectedDate, t, m.styles, m.config.Categories, m.store)
+				modal.width = m.width
+				modal.height = m.height
+				m.modalStack = append(m.modalStack, modal)
+				return m, modal.Init()
+			}
 			if m.currentView == ListView {
 				if evt := m.listView.GetSelectedEvent(); evt != nil {
-					modal := NewEventModal(evt.Date, evt.Event, m.styles, m.config.Categories)
+					modal := NewEventModal(evt.Date, evt.Event, m.styles, m.config.Categories, m.store)
 					modal.width = m.width
 					modal.height = m.height
 					m.modalStack = append(m.modalStack, modal)
@@ -546,7 +739,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Edit from agenda (works regardless of focus)
 				if idx := m.agendaView.GetSelectedIndex(); idx >= 0 && idx < len(m.events) {
 					event := m.events[idx]
-					modal := NewEventModal(m.selectedDate, event, m.styles, m.config.Categories)
+					modal := NewEventModal(m.selectedDate, event, m.styles, m.config.Categories, m.store)
 					modal.width = m.width
 					modal.height = m.height
 					m.modalStack = append(m.modalStack, modal)
@@ -554,11 +747,104 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			
+		case "/":
+			// Search events
+			modal := NewSearchModal(m.selectedDate, m.styles, m.store)
+			modal.width = m.width
+			modal.height = m.height
+			m.modalStack = append(m.modalStack, modal)
+			return m, modal.Init()
+			
+		case "o", "O":
+			// Open the selected event's link, or copy it with O
+			if evt := m.selectedEvent(); evt != nil {
+				switch {
+				case evt.URL == "":
+					m.statusMsg = "No link on this event"
+				case msg.String() == "O":
+					if err := copyToClipboard(evt.URL); err != nil {
+						m.statusMsg = err.Error()
+					} else {
+						m.statusMsg = "Copied " + evt.URL
+					}
+				default:
+					if err := openURL(evt.URL); err != nil {
+						m.statusMsg = err.Error()
+					} else {
+						m.statusMsg = "Opened " + evt.URL
+					}
+				}
+			}
+
+		case "x":
+			// Cycle the selected event's status
+			date := m.selectedDate
+			evt := m.selectedEvent()
+			if m.currentView == ListView {
+				if sel := m.listView.GetSelectedEvent(); sel != nil {
+					date = sel.Date
+				}
+			}
+			if evt != nil {
+				if status, err := cycleStatus(m.store, date, evt); err != nil {
+					m.statusMsg = err.Error()
+				} else {
+					m.statusMsg = evt.Title + ": " + statusLabel(status)
+					m.stale = true
+				}
+			}
+
+		case "N":
+			// View or edit the selected day's note
+			date := m.selectedDate
+			if m.currentView == ListView {
+				if sel := m.listView.GetSelectedEvent(); sel != nil {
+					date = sel.Date
+				}
+			}
+			modal := NewNoteModal(date, m.data.noteOn(date), m.styles, m.store)
+			modal.width = m.width
+			modal.height = m.height
+			m.modalStack = append(m.modalStack, modal)
+			return m, modal.Init()
+			
+		case "n":
+			// Toggle note excerpts in the list view
+			if m.currentView == ListView {
+				m.config.ListNotes = !m.config.ListNotes
+				m.config.Save()
+			}
+			
+		case "T":
+			// Add a task due on the selected date
+			modal := NewTaskModal(m.selectedDate, nil, m.styles, m.config.Categories, m.store)
+			modal.width = m.width
+			modal.height = m.height
+			m.modalStack = append(m.modalStack, modal)
+			return m, modal.Init()
+			
+		case " ":
+			// Tick the selected task off, or back on
+			if t := m.selectedTask(); t != nil {
+				if err := toggleTask(m.store, t); err != nil {
+					m.statusMsg = err.Error()
+				} else {
+					m.stale = true
+				}
+			}
+			
 		case "d":
 			// Delete selected event (works on agenda or list view)
+			if t := m.selectedTask(); t != nil {
+				modal := NewDeleteTaskModal(t, m.styles, m.filter)
+				modal.width = m.width
+				modal.height = m.height
+				m.modalStack = append(m.modalStack, modal)
+				return m, modal.Init()
+			}
 			if m.currentView == ListView {
 				if evt := m.listView.GetSelectedEvent(); evt != nil {
-					modal := NewDeleteModal(evt.Date, evt.Event, 0, m.styles)
+					modal := NewDeleteModal(evt.Date, evt.Event, 0, m.styles, m.store)
 					modal.width = m.width
 					modal.height = m.height
 					m.modalStack = append(m.modalStack, modal)
@@ -568,7 +854,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Delete from agenda (works regardless of focus)
 				if idx := m.agendaView.GetSelectedIndex(); idx >= 0 && idx < len(m.events) {
 					event := m.events[idx]
-					modal := NewDeleteModal(m.selectedDate, event, idx, m.styles)
+					modal := NewDeleteModal(m.selectedDate, event, idx, m.styles, m.store)
 					modal.width = m.width
 					modal.height = m.height
 					m.modalStack = append(m.modalStack, modal)
@@ -582,15 +868,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			
 		default:
 			// hjkl always control calendar, other keys depend on focus
-			oldDate := m.selectedDate
 			cmd := m.handleCalendarNavigation(msg)
 			if cmd != nil {
 				cmds = append(cmds, cmd)
 			}
-			if !sameDay(oldDate, m.selectedDate) {
-				m.loadEvents()
-				cmds = append(cmds, loadEventsCmd(m.selectedDate))
-			}
 			
 		}
 	}
@@ -598,6 +879,30 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// selectedEvent returns the event selected in the list view, or in the
+// agenda in the other views
+func (m *Model) selectedEvent() *model.Event {
+	if m.currentView == ListView {
+		if evt := m.listView.GetSelectedEvent(); evt != nil {
+			return evt.Event
+		}
+		return nil
+	}
+	if idx := m.agendaView.GetSelectedIndex(); idx >= 0 && idx < len(m.events) {
+		return m.events[idx]
+	}
+	return nil
+}
+
+// selectedTask returns the task selected in the list view, or in the
+// agenda in the other views
+func (m *Model) selectedTask() *model.Task {
+	if m.currentView == ListView {
+		return m.listView.GetSelectedTask()
+	}
+	return m.agendaView.GetSelectedTask()
+}
+
 func (m *Model) handleAgendaArrowKeys(msg tea.KeyMsg) {
 	switch msg.String() {
 	case "up":
@@ -676,32 +981,34 @@ func (m *Model) updateViewStyles() {
 	// Update all views with new styles
 	if m.monthView != nil {
 		width, height := m.monthView.width, m.monthView.height
-		m.monthView = NewMonthViewModel(&m.selectedDate, m.styles, m.config)
+		m.monthView = NewMonthViewModel(&m.selectedDate, m.styles, m.config, &m.data)
 		m.monthView.SetSize(width, height)
 	}
 	if m.weekView != nil {
 		width, height := m.weekView.width, m.weekView.height
 		showMiniMonth := m.weekView.showMiniMonth
-		m.weekView = NewWeekViewModel(&m.selectedDate, &m.selectedHour, m.styles, m.config)
+		m.weekView = NewWeekViewModel(&m.selectedDate, &m.selectedHour, m.styles, m.config, &m.data)
 		m.weekView.SetShowMiniMonth(showMiniMonth)
 		m.weekView.SetSize(width, height)
 	}
 	if m.dayView != nil {
 		width, height := m.dayView.width, m.dayView.height
-		m.dayView = NewDayViewModel(&m.selectedDate, &m.selectedHour, m.styles, m.config)
+		m.dayView = NewDayViewModel(&m.selectedDate, &m.selectedHour, m.styles, m.config, &m.data)
 		m.dayView.SetSize(width, height)
 	}
 	if m.listView != nil {
 		width, height := m.listView.width, m.listView.height
-		m.listView = NewListViewModel(&m.selectedDate, m.styles, m.config)
+		m.listView = NewListViewModel(&m.selectedDate, m.styles, m.config, &m.data)
 		m.listView.SetSize(width, height)
 	}
 	if m.agendaView != nil {
 		width, height := m.agendaView.width, m.agendaView.height
 		events := m.agendaView.events
+		tasks := m.agendaView.tasks
 		selectedIndex := m.agendaView.selectedIndex
 		m.agendaView = NewAgendaViewModel(&m.selectedDate, m.styles, m.config)
 		m.agendaView.SetEvents(events)
+		m.agendaView.SetTasks(tasks)
 		m.agendaView.selectedIndex = selectedIndex
 		m.agendaView.SetSize(width, height)
 	}
@@ -760,11 +1067,104 @@ func (m *Model) updateViewSizes() {
 	}
 }
 
+// dataRange returns the days the current view draws
+func (m *Model) dataRange() (time.Time, time.Time) {
+	if m.currentView == ListView {
+		return listRange(currentTime(m.config), m.listView.daysToShow)
+	}
+	// The month grid around the selected date holds its week too
+	return monthGridRange(m.selectedDate)
+}
+
+// loadData starts loading the days the current view draws unless they
+// are loaded or on their way already, or reloads them after a write. A
+// load still running for other days is cancelled, so moving quickly
+// through the calendar only waits for the last stop. Until a load
+// finishes, the views show what was loaded before or a placeholder.
+func (m *Model) loadData() tea.Cmd {
+	m.loadEvents()
+	
+	from, to := m.dataRange()
+	pending := m.loading &&
+		m.loadFrom.Format(model.DateFormat) <= from.Format(model.DateFormat) &&
+		m.loadTo.Format(model.DateFormat) >= to.Format(model.DateFormat)
+	if !m.stale && (m.data.covers(from, to) || pending) {
+		return nil
+	}
+	
+	if m.cancelLoad != nil {
+		m.cancelLoad()
+	}
+	var ctx context.Context
+	ctx, m.cancelLoad = context.WithCancel(context.Background())
+	m.loadSeq++
+	m.loading = true
+	m.loadFrom, m.loadTo = from, to
+	m.stale = false
+	// Load through a copy of the filter, so changing it doesn't race
+	// with the load
+	filter := *m.filter
+	return loadDataCmd(ctx, m.loadSeq, &filter, from, to)
+}
+
+// applyFileChanges reloads what other programs changed on disk
+func (m *Model) applyFileChanges(msg FilesChangedMsg) {
+	if msg.changes.Shared {
+		m.cache.Invalidate()
+	} else {
+		for _, key := range msg.changes.Days {
+			m.cache.InvalidateDay(key)
+		}
+	}
+	if !msg.changes.Empty() {
+		m.stale = true
+	}
+	if msg.config {
+		m.reloadConfig()
+	}
+}
+
+// reloadConfig applies config.json after it changed on disk. A file that
+// doesn't parse, say because it is being written, is left for the next
+// change.
+func (m *Model) reloadConfig() {
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	zone := m.config.Location()
+	*m.config = *cfg
+	
+	if m.showMiniMonth != cfg.ShowMiniMonth {
+		m.showMiniMonth = cfg.ShowMiniMonth
+		m.weekView.SetShowMiniMonth(m.showMiniMonth)
+	}
+	if m.agendaBottom != cfg.AgendaBottom {
+		m.agendaBottom = cfg.AgendaBottom
+		m.updateViewSizes()
+	}
+	if m.currentTheme != ThemeType(cfg.Theme) {
+		m.currentTheme = ThemeType(cfg.Theme)
+		m.styles = GetStyles(m.currentTheme)
+		m.updateViewStyles()
+	}
+	if loc := cfg.Location(); loc.String() != zone.String() {
+		// Keep the selected day, now in the new zone
+		y, mo, d := m.selectedDate.Date()
+		m.selectedDate = time.Date(y, mo, d, m.selectedDate.Hour(), m.selectedDate.Minute(), 0, 0, loc)
+		m.filter.Store = storage.NewZonedStore(m.undoLog, loc)
+	}
+	m.stale = true
+}
+
+// loadEvents points the agenda at the loaded events and tasks of the
+// selected date
 func (m *Model) loadEvents() {
-	events, _ := storage.LoadDayEvents(m.selectedDate)
-	m.events = events
+	m.events = m.data.eventsOn(m.selectedDate)
+	m.tasks = model.TasksOn(m.data.tasks, m.selectedDate, currentTime(m.config))
 	if m.agendaView != nil {
 		m.agendaView.SetEvents(m.events)
+		m.agendaView.SetTasks(m.tasks)
 	}
 }
 
@@ -893,6 +1293,22 @@ func (m *Model) renderHeader() string {
 		headerText += " · " + jumpStatus
 	}
 	
+	if m.filter.category != "" {
+		filterStatus := lipgloss.NewStyle().
+			Background(lipgloss.Color(m.config.GetCategoryColor(m.filter.category))).
+			Foreground(lipgloss.Color("0")).
+			Bold(true).
+			Padding(0, 1).
+			Render("🏷️ " + m.filter.category)
+		headerText += " · " + filterStatus
+	}
+	
+	if m.statusMsg != "" {
+		headerText += " · " + lipgloss.NewStyle().
+			Foreground(lipgloss.Color("229")).
+			Render(m.statusMsg)
+	}
+	
 	if m.yankedEvent != nil {
 		yankStatus := lipgloss.NewStyle().
 			Background(lipgloss.Color("28")).
@@ -956,6 +1372,14 @@ func (m *Model) renderHeader() string {
 
 // Helper functions
 
+// currentTime returns the time in the configured display zone
+func currentTime(cfg *config.Config) time.Time {
+	if cfg == nil {
+		return time.Now()
+	}
+	return time.Now().In(cfg.Location())
+}
+
 func sameDay(a, b time.Time) bool {
 	ay, am, ad := a.Date()
 	by, bm, bd := b.Date()
@@ -964,21 +1388,8 @@ func sameDay(a, b time.Time) bool {
 
 // Messages
 
-type EventsLoadedMsg struct {
-	Events []*model.Event
-}
-
 type ModalCloseMsg bool
 
-// Commands
-
-func loadEventsCmd(date time.Time) tea.Cmd {
-	return func() tea.Msg {
-		events, _ := storage.LoadDayEvents(date)
-		return EventsLoadedMsg{Events: events}
-	}
-}
-
 // Jump mode functions
 
 func (m *Model) initJumpMode(jumpType string) {
@@ -1032,7 +1443,6 @@ func (m *Model) handleJumpMode(key string) *Model {
 				} else if i < len(m.jumpTargets) {
 					// Jump to calendar date
 					m.selectedDate = m.jumpTargets[i]
-					m.loadEvents()
 				}
 			} else if m.jumpModeType == "agenda" {
 				if m.currentView == ListView {
@@ -1127,16 +1537,13 @@ func (m *Model) generateListJumpTargets() {
 // Helper functions for dynamic hour ranges
 
 func (m *Model) getEarliestHourForDay() int {
-	events, _ := storage.LoadDayEvents(m.selectedDate)
+	events := m.data.eventsOn(m.selectedDate)
 	minHour := 6 // Default
 	
 	for _, evt := range events {
-		if !evt.IsAllDay() && evt.StartTime != "" {
-			var hour int
-			if _, err := fmt.Sscanf(evt.StartTime, "%d:", &hour); err == nil {
-				if hour < minHour {
-					minHour = hour
-				}
+		if hour, _, ok := evt.SegmentOn(m.selectedDate).HourRange(); ok {
+			if hour < minHour {
+				minHour = hour
 			}
 		}
 	}
@@ -1148,16 +1555,17 @@ func (m *Model) getEarliestHourForDay() int {
 }
 
 func (m *Model) getLatestHourForDay() int {
-	events, _ := storage.LoadDayEvents(m.selectedDate)
+	events := m.data.eventsOn(m.selectedDate)
 	maxHour := 22 // Default
 	
 	for _, evt := range events {
-		if !evt.IsAllDay() && evt.StartTime != "" {
-			var hour int
-			if _, err := fmt.Sscanf(evt.StartTime, "%d:", &hour); err == nil {
-				if hour > maxHour {
-					maxHour = hour
-				}
+		if first, last, ok := evt.SegmentOn(m.selectedDate).HourRange(); ok {
+			// Events running past midnight occupy the rest of the day
+			if !evt.IsMultiDay() {
+				last = first
+			}
+			if last > maxHour {
+				maxHour = last
 			}
 		}
 	}
@@ -1174,15 +1582,12 @@ func (m *Model) getEarliestHourForWeek() int {
 	
 	for d := 0; d < 7; d++ {
 		date := weekStart.AddDate(0, 0, d)
-		events, _ := storage.LoadDayEvents(date)
+		events := m.data.eventsOn(date)
 		
 		for _, evt := range events {
-			if !evt.IsAllDay() && evt.StartTime != "" {
-				var hour int
-				if _, err := fmt.Sscanf(evt.StartTime, "%d:", &hour); err == nil {
-					if hour < minHour {
-						minHour = hour
-					}
+			if hour, _, ok := evt.SegmentOn(date).HourRange(); ok {
+				if hour < minHour {
+					minHour = hour
 				}
 			}
 		}
@@ -1200,15 +1605,15 @@ func (m *Model) getLatestHourForWeek() int {
 	
 	for d := 0; d < 7; d++ {
 		date := weekStart.AddDate(0, 0, d)
-		events, _ := storage.LoadDayEvents(date)
+		events := m.data.eventsOn(date)
 		
 		for _, evt := range events {
-			if !evt.IsAllDay() && evt.StartTime != "" {
-				var hour int
-				if _, err := fmt.Sscanf(evt.StartTime, "%d:", &hour); err == nil {
-					if hour > maxHour {
-						maxHour = hour
-					}
+			if first, last, ok := evt.SegmentOn(date).HourRange(); ok {
+				if !evt.IsMultiDay() {
+					last = first
+				}
+				if last > maxHour {
+					maxHour = last
 				}
 			}
 		}
@@ -3,7 +3,7 @@ package tui
 import (
 	"fmt"
 	"bubblecal/internal/config"
-	"bubblecal/internal/storage"
+	"bubblecal/internal/model"
 	"strings"
 	"time"
 
@@ -17,6 +17,7 @@ type MonthViewModel struct {
 	width        int
 	height       int
 	config       *config.Config
+	data         *calendarData
 	// Jump mode state
 	jumpMode     bool
 	jumpKeys     []string
@@ -24,11 +25,12 @@ type MonthViewModel struct {
 }
 
 // NewMonthViewModel creates a new month view model
-func NewMonthViewModel(selectedDate *time.Time, styles *Styles, config *config.Config) *MonthViewModel {
+func NewMonthViewModel(selectedDate *time.Time, styles *Styles, config *config.Config, data *calendarData) *MonthViewModel {
 	return &MonthViewModel{
 		selectedDate: selectedDate,
 		styles:       styles,
 		config:       config,
+		data:         data,
 	}
 }
 
@@ -49,6 +51,9 @@ func (m *MonthViewModel) View() string {
 	}
 	
 	now := *m.selectedDate
+	if !m.data.covers(monthGridRange(now)) {
+		return loadingView(m.width, m.height)
+	}
 	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	startWeekday := int(firstOfMonth.Weekday())
 	
@@ -83,7 +88,6 @@ func (m *MonthViewModel) View() string {
 	prevLast := firstOfMonth.AddDate(0, 0, -1)
 	
 	// Build calendar grid
-	var currentWeek []string
 	var currentWeekDates []time.Time
 	
 	// Fill leading days from previous month
@@ -100,17 +104,7 @@ func (m *MonthViewModel) View() string {
 		
 		// End of week
 		if len(currentWeekDates) == 7 {
-			// Calculate max height for this week
-			maxHeight := m.getMaxHeightForWeek(currentWeekDates)
-			
-			// Render all cells with consistent height
-			for _, d := range currentWeekDates {
-				isOtherMonth := d.Month() != now.Month()
-				currentWeek = append(currentWeek, m.renderDayCellWithHeight(d, isOtherMonth, cellWidth, maxHeight))
-			}
-			
-			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, currentWeek...))
-			currentWeek = []string{}
+			lines = append(lines, m.renderWeek(currentWeekDates, now.Month(), cellWidth))
 			currentWeekDates = []time.Time{}
 		}
 	}
@@ -124,16 +118,7 @@ func (m *MonthViewModel) View() string {
 			nextDay++
 		}
 		
-		// Calculate max height for this week
-		maxHeight := m.getMaxHeightForWeek(currentWeekDates)
-		
-		// Render all cells with consistent height
-		for _, d := range currentWeekDates {
-			isOtherMonth := d.Month() != now.Month()
-			currentWeek = append(currentWeek, m.renderDayCellWithHeight(d, isOtherMonth, cellWidth, maxHeight))
-		}
-		
-		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, currentWeek...))
+		lines = append(lines, m.renderWeek(currentWeekDates, now.Month(), cellWidth))
 	}
 	
 	// Ensure we have 6 weeks for consistent height
@@ -148,23 +133,47 @@ func (m *MonthViewModel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
-func (m *MonthViewModel) getMaxHeightForWeek(dates []time.Time) int {
+// renderWeek renders one row of the month with consistent cell heights
+// and multi-day events drawn as bars across the cells they cover
+func (m *MonthViewModel) renderWeek(dates []time.Time, month time.Month, cellWidth int) string {
+	eventsByDay := make([][]*model.Event, len(dates))
+	for i, date := range dates {
+		eventsByDay[i] = m.data.eventsOn(date)
+	}
+	// Overnight events are counted with the timed events of their first day
+	lanes := assignSpanLanes(dates, eventsByDay, func(evt *model.Event, _ time.Time) bool {
+		return !evt.IsOvernight()
+	})
+	
+	// Calculate max height for this week
+	maxHeight := m.getMaxHeightForWeek(eventsByDay, lanes)
+	
+	// Render all cells with consistent height
+	var cells []string
+	for i, d := range dates {
+		isOtherMonth := d.Month() != month
+		cells = append(cells, m.renderDayCellWithHeight(d, i, eventsByDay[i], lanes, isOtherMonth, cellWidth, maxHeight))
+	}
+	
+	return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+}
+
+func (m *MonthViewModel) getMaxHeightForWeek(eventsByDay [][]*model.Event, lanes spanLanes) int {
 	maxHeight := 2 // Minimum height
 	
-	for _, date := range dates {
-		events, _ := storage.LoadDayEvents(date)
+	for _, events := range eventsByDay {
 		allDayCount := 0
 		
 		for _, evt := range events {
-			if evt.IsAllDay() {
+			if evt.IsAllDay() && !evt.IsMultiDay() {
 				allDayCount++
 			}
 		}
 		
 		// Calculate needed height for this cell
 		cellHeight := 2
-		if allDayCount > 0 {
-			cellHeight = 1 + allDayCount // 1 for date line + 1 per all-day event
+		if allDayCount+lanes.count > 0 {
+			cellHeight = 1 + lanes.count + allDayCount // 1 for date line + span lanes + 1 per all-day event
 		}
 		
 		if cellHeight > maxHeight {
@@ -175,16 +184,16 @@ func (m *MonthViewModel) getMaxHeightForWeek(dates []time.Time) int {
 	return maxHeight
 }
 
-func (m *MonthViewModel) renderDayCellWithHeight(date time.Time, otherMonth bool, width int, height int) string {
+func (m *MonthViewModel) renderDayCellWithHeight(date time.Time, col int, events []*model.Event, lanes spanLanes, otherMonth bool, width int, height int) string {
 	dayNum := fmt.Sprintf("%2d", date.Day())
 	
-	// Base style with specified height
+	// Base style with specified height. Padding is added per line so that
+	// multi-day bars can run edge to edge into the neighbouring cells.
 	style := lipgloss.NewStyle().
 		Width(width).
-		Height(height).
-		Padding(0, 1)
+		Height(height)
 	
-	today := time.Now()
+	today := currentTime(m.config)
 	
 	// Apply styling based on date properties
 	if otherMonth {
@@ -208,30 +217,36 @@ func (m *MonthViewModel) renderDayCellWithHeight(date time.Time, otherMonth bool
 			Foreground(m.styles.TodayDate.GetForeground())
 	}
 	
-	// Load events
-	events, _ := storage.LoadDayEvents(date)
 	eventInfo := ""
 	dayDisplay := dayNum // Initialize here
 	
-	if len(events) > 0 {
+	if len(events) > 0 || lanes.count > 0 {
+		// One line per span lane, blank where no bar crosses this day
+		spanLines := make([]string, lanes.count)
 		// Collect all all-day events
 		var allDayEvents []string
 		timedEventCount := 0
 		
 		for _, evt := range events {
-			if evt.IsAllDay() {
-				title := evt.Title
-				maxLen := width - 6
-				if len(title) > maxLen && maxLen > 3 {
-					title = title[:maxLen-1] + "…"
+			// Get category color
+			categoryColor := "#808080"
+			if m.config != nil && evt.PrimaryCategory() != "" {
+				categoryColor = m.config.GetCategoryColor(evt.PrimaryCategory())
+			}
+			
+			if evt.IsOvernight() {
+				if !evt.SegmentOn(date).ContinuesBefore {
+					timedEventCount++
 				}
-				// Get category color
-				categoryColor := "#808080"
-				if m.config != nil && evt.Category != "" {
-					categoryColor = m.config.GetCategoryColor(evt.Category)
+			} else if evt.IsMultiDay() {
+				if lane, ok := lanes.lanes[spanKey(evt)]; ok {
+					showTitle := lanes.first[spanKey(evt)] == col
+					spanLines[lane] = renderSpanBar(evt, date, showTitle, width, lipgloss.Color(categoryColor))
 				}
-				eventStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(categoryColor))
-				allDayEvents = append(allDayEvents, eventStyle.Render(title))
+			} else if evt.IsAllDay() {
+				title := truncateText(eventTitle(evt), width-4)
+				eventStyle := withStatus(lipgloss.NewStyle().Foreground(lipgloss.Color(categoryColor)), evt)
+				allDayEvents = append(allDayEvents, " "+eventStyle.Render(title))
 			} else {
 				timedEventCount++
 			}
@@ -242,8 +257,8 @@ func (m *MonthViewModel) renderDayCellWithHeight(date time.Time, otherMonth bool
 			// Use first timed event's category color for the indicator
 			indicatorColor := "#808080"
 			for _, evt := range events {
-				if !evt.IsAllDay() && m.config != nil && evt.Category != "" {
-					indicatorColor = m.config.GetCategoryColor(evt.Category)
+				if !evt.IsAllDay() && !evt.IsMultiDay() && m.config != nil && evt.PrimaryCategory() != "" {
+					indicatorColor = m.config.GetCategoryColor(evt.PrimaryCategory())
 					break
 				}
 			}
@@ -251,11 +266,26 @@ func (m *MonthViewModel) renderDayCellWithHeight(date time.Time, otherMonth bool
 			dayDisplay += " " + indicatorStyle.Render(fmt.Sprintf("●%d", timedEventCount))
 		}
 		
-		// Add all-day events on separate lines below
-		if len(allDayEvents) > 0 {
-			eventInfo = "\n" + strings.Join(allDayEvents, "\n")
+		// Add span bars and all-day events on separate lines below
+		if lines := append(spanLines, allDayEvents...); len(lines) > 0 {
+			eventInfo = "\n" + strings.Join(lines, "\n")
+		}
+	}
+	
+	if m.data.noteOn(date) != "" {
+		dayDisplay += " " + lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Render(noteMarker)
+	}
+	
+	// Open tasks get their own badge next to the event count
+	open := 0
+	for _, t := range model.TasksOn(m.data.tasks, date, today) {
+		if !t.Done {
+			open++
 		}
 	}
+	if open > 0 {
+		dayDisplay += " " + lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(fmt.Sprintf("☐%d", open))
+	}
 	
 	// Add jump key overlay if in jump mode
 	if m.jumpMode {
@@ -275,7 +305,7 @@ func (m *MonthViewModel) renderDayCellWithHeight(date time.Time, otherMonth bool
 	}
 	
 	// Compose the cell content
-	return style.Render(dayDisplay + eventInfo)
+	return style.Render(" " + dayDisplay + eventInfo)
 }
 
 // Keep the old renderDayCell for compatibility (not used anymore)
@@ -283,7 +313,7 @@ func (m *MonthViewModel) renderDayCell(date time.Time, otherMonth bool, width in
 	dayNum := fmt.Sprintf("%2d", date.Day())
 	
 	// Load events once
-	events, _ := storage.LoadDayEvents(date)
+	events := m.data.eventsOn(date)
 	
 	// Calculate cell height based on events
 	cellHeight := 2 // Minimum height
@@ -315,7 +345,7 @@ func (m *MonthViewModel) renderDayCell(date time.Time, otherMonth bool, width in
 		Height(cellHeight).
 		Padding(0, 1)
 	
-	today := time.Now()
+	today := currentTime(m.config)
 	
 	// Apply styling based on date properties
 	if otherMonth {
@@ -350,17 +380,17 @@ func (m *MonthViewModel) renderDayCell(date time.Time, otherMonth bool, width in
 		
 		for _, evt := range events {
 			if evt.IsAllDay() {
-				title := evt.Title
+				title := eventTitle(evt)
 				maxLen := width - 6
 				if len(title) > maxLen && maxLen > 3 {
 					title = title[:maxLen-1] + "…"
 				}
 				// Get category color
 				categoryColor := "#808080"
-				if m.config != nil && evt.Category != "" {
-					categoryColor = m.config.GetCategoryColor(evt.Category)
+				if m.config != nil && evt.PrimaryCategory() != "" {
+					categoryColor = m.config.GetCategoryColor(evt.PrimaryCategory())
 				}
-				eventStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(categoryColor))
+				eventStyle := withStatus(lipgloss.NewStyle().Foreground(lipgloss.Color(categoryColor)), evt)
 				allDayEvents = append(allDayEvents, eventStyle.Render(title))
 			} else {
 				timedEventCount++
@@ -372,8 +402,8 @@ func (m *MonthViewModel) renderDayCell(date time.Time, otherMonth bool, width in
 			// Use first timed event's category color for the indicator
 			indicatorColor := "#808080"
 			for _, evt := range events {
-				if !evt.IsAllDay() && m.config != nil && evt.Category != "" {
-					indicatorColor = m.config.GetCategoryColor(evt.Category)
+				if !evt.IsAllDay() && m.config != nil && evt.PrimaryCategory() != "" {
+					indicatorColor = m.config.GetCategoryColor(evt.PrimaryCategory())
 					break
 				}
 			}
@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"bubblecal/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// noteMarker is shown next to the dates of days with a note
+const noteMarker = "✎"
+
+// noteExcerpt returns the first non-empty line of a note, without
+// Markdown heading marks
+func noteExcerpt(note string) string {
+	for _, line := range strings.Split(note, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "# "))
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// NoteModal shows and edits the note of a day
+type NoteModal struct {
+	date     time.Time
+	text     textarea.Model
+	original string
+	errorMsg string
+	store    storage.Store
+	styles   *Styles
+	width    int
+	height   int
+}
+
+func NewNoteModal(date time.Time, note string, styles *Styles, store storage.Store) *NoteModal {
+	text := textarea.New()
+	text.Placeholder = "Notes for the day (Markdown)"
+	text.SetWidth(66)
+	text.SetHeight(12)
+	text.CharLimit = 20000
+	text.ShowLineNumbers = false
+	text.SetValue(note)
+	text.Focus()
+	return &NoteModal{
+		date:     date,
+		text:     text,
+		original: note,
+		store:    store,
+		styles:   styles,
+	}
+}
+
+func (m *NoteModal) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+func (m *NoteModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+
+		case "ctrl+s":
+			if m.text.Value() != m.original {
+				ns, ok := m.store.(storage.NoteStore)
+				if !ok {
+					m.errorMsg = "this calendar doesn't keep notes"
+					return m, nil
+				}
+				if err := ns.SaveNote(m.date, m.text.Value()); err != nil {
+					m.errorMsg = err.Error()
+					return m, nil
+				}
+			}
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+		}
+
+		var cmd tea.Cmd
+		m.text, cmd = m.text.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *NoteModal) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	gray := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	content := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33")).Render("📓 Day Note"),
+		gray.Render(m.date.Format("Monday, January 2, 2006")),
+		"",
+		m.text.View(),
+		"",
+	}
+	if m.errorMsg != "" {
+		content = append(content, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true).
+			Render("❌ "+m.errorMsg), "")
+	}
+	content = append(content, gray.Render("Ctrl+S Save · Esc Discard changes · Save an empty note to delete it"))
+
+	modal := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2).
+		Width(74).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
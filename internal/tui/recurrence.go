@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// recurringMarker is shown in front of the titles of recurring events
+const recurringMarker = "↻ "
+
+// eventTitle returns the title shown for an event in the views
+func eventTitle(evt *model.Event) string {
+	if evt.InSeries() {
+		return recurringMarker + evt.Title
+	}
+	return evt.Title
+}
+
+// repeatShorthands maps the words accepted in the event modal's repeat
+// field to RRULE values
+var repeatShorthands = map[string]string{
+	"daily":    "FREQ=DAILY",
+	"weekdays": "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+	"weekly":   "FREQ=WEEKLY",
+	"biweekly": "FREQ=WEEKLY;INTERVAL=2",
+	"monthly":  "FREQ=MONTHLY",
+	"yearly":   "FREQ=YEARLY",
+}
+
+// parseRepeat parses the repeat field: empty for a one-off event, one of
+// the shorthands above, or an RRULE such as "FREQ=WEEKLY;BYDAY=MO,TH"
+func parseRepeat(input string) (*model.Recurrence, error) {
+	input = strings.TrimSpace(input)
+	if input == "" || strings.EqualFold(input, "none") {
+		return nil, nil
+	}
+	if rule, ok := repeatShorthands[strings.ToLower(input)]; ok {
+		input = rule
+	}
+	rule, err := model.ParseRecurrence(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repeat rule: %v", err)
+	}
+	return rule, nil
+}
+
+// formatRepeat is the inverse of parseRepeat, preferring shorthands
+func formatRepeat(rule *model.Recurrence) string {
+	if rule == nil {
+		return ""
+	}
+	value := rule.String()
+	for word, shorthand := range repeatShorthands {
+		if shorthand == value {
+			return word
+		}
+	}
+	return value
+}
+
+// seriesScopes are the choices offered when editing or deleting an
+// occurrence of a recurring event, in display order
+var seriesScopes = []storage.Scope{storage.ScopeThis, storage.ScopeFollowing, storage.ScopeAll}
+
+// scopeKeys are the shortcuts for seriesScopes
+var scopeKeys = []string{"t", "f", "a"}
+
+// scopeForKey returns the index in seriesScopes for a shortcut key, or -1
+func scopeForKey(key string) int {
+	for i, k := range scopeKeys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// renderScopePrompt renders the "this / this and following / all" choice
+func renderScopePrompt(question string, selected int) string {
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(question)}
+	for i, scope := range seriesScopes {
+		option := fmt.Sprintf("[%s] %s", strings.ToUpper(scopeKeys[i]), scope)
+		if i == selected {
+			option = lipgloss.NewStyle().
+				Background(lipgloss.Color("39")).
+				Foreground(lipgloss.Color("0")).
+				Bold(true).
+				Render("▶ " + option)
+		} else {
+			option = "  " + option
+		}
+		lines = append(lines, option)
+	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("↑↓ Choose  Enter Confirm  Esc Back"))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"bubblecal/internal/config"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingStore counts the days and notes read from a FileStore
+type countingStore struct {
+	*storage.FileStore
+	reads int
+}
+
+func (s *countingStore) LoadDayEvents(date time.Time) ([]*model.Event, error) {
+	s.reads++
+	return s.FileStore.LoadDayEvents(date)
+}
+
+func (s *countingStore) LoadNote(date time.Time) (string, error) {
+	s.reads++
+	return s.FileStore.LoadNote(date)
+}
+
+// cachedCalendar returns a cache over a calendar with a few events a day
+// in August 2025, and the store it counts reads of
+func cachedCalendar(b *testing.B) (*storage.CachedStore, *countingStore) {
+	b.Helper()
+	files := storage.NewFileStore(b.TempDir())
+	for d := 1; d <= 31; d++ {
+		date := time.Date(2025, 8, d, 0, 0, 0, 0, time.Local)
+		if err := files.SaveEvent(date, &model.Event{StartTime: "all-day", Title: "Offsite"}); err != nil {
+			b.Fatal(err)
+		}
+		for h := 9; h < 12; h++ {
+			event := &model.Event{
+				StartTime:  fmt.Sprintf("%02d:00", h),
+				EndTime:    fmt.Sprintf("%02d:30", h),
+				Title:      fmt.Sprintf("Meeting %d", h),
+				Categories: []string{"Work"},
+			}
+			if err := files.SaveEvent(date, event); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	counted := &countingStore{FileStore: files}
+	return storage.NewCachedStore(counted), counted
+}
+
+// benchmarkView loads the days from to to through cache on every
+// iteration, as the model does after each key press, and renders view,
+// which should show want. It fails if anything but the first load reads
+// the store.
+func benchmarkView(b *testing.B, cache *storage.CachedStore, counted *countingStore, from, to time.Time, data *calendarData, view func() string, want string) {
+	load := func() {
+		msg, ok := loadDataCmd(context.Background(), 0, cache, from, to)().(DataLoadedMsg)
+		if !ok {
+			b.Fatal("load was cancelled")
+		}
+		*data = msg.data
+	}
+	load()
+	if !strings.Contains(view(), want) {
+		b.Fatal("the view shows no events")
+	}
+	first := counted.reads
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		load()
+		view()
+	}
+	b.StopTimer()
+
+	reads := counted.reads - first
+	b.ReportMetric(float64(first), "first-load-reads")
+	b.ReportMetric(float64(reads)/float64(b.N), "store-reads/op")
+	if reads != 0 {
+		b.Fatalf("%d store reads after the first load", reads)
+	}
+}
+
+func BenchmarkMonthView(b *testing.B) {
+	cache, counted := cachedCalendar(b)
+	selected := time.Date(2025, 8, 13, 0, 0, 0, 0, time.Local)
+	data := &calendarData{}
+	view := NewMonthViewModel(&selected, DefaultStyles(), config.DefaultConfig(), data)
+	view.SetSize(160, 48)
+	from, to := monthGridRange(selected)
+	benchmarkView(b, cache, counted, from, to, data, view.View, "Offsite")
+}
+
+func BenchmarkWeekView(b *testing.B) {
+	cache, counted := cachedCalendar(b)
+	selected := time.Date(2025, 8, 13, 0, 0, 0, 0, time.Local)
+	hour := 10
+	data := &calendarData{}
+	view := NewWeekViewModel(&selected, &hour, DefaultStyles(), config.DefaultConfig(), data)
+	view.SetSize(160, 48)
+	// The week view's mini month needs the month grid too
+	from, to := monthGridRange(selected)
+	benchmarkView(b, cache, counted, from, to, data, view.View, "Meeting")
+}
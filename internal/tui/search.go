@@ -0,0 +1,234 @@
+package tui
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// searchDays is how far before and after the selected date search looks
+const searchDays = 365
+
+// maxSearchResults bounds the result list
+const maxSearchResults = 50
+
+// JumpToDateMsg asks the main model to select a date
+type JumpToDateMsg time.Time
+
+// searchResultsMsg carries the matches of a searchCmd
+type searchResultsMsg struct {
+	seq     int
+	results []EventWithDate
+}
+
+// SearchModal finds events by title, description, location, link,
+// attendees or category
+type SearchModal struct {
+	date     time.Time
+	input    textinput.Model
+	results  []EventWithDate
+	selected int
+	searched bool
+	searching bool // a search is running in the background
+	seq      int  // identifies the latest search, to drop stale results
+	store    storage.Store
+	styles   *Styles
+	width    int
+	height   int
+}
+
+func NewSearchModal(date time.Time, styles *Styles, store storage.Store) *SearchModal {
+	input := textinput.New()
+	input.Placeholder = "Search events"
+	input.CharLimit = 100
+	input.Focus()
+	return &SearchModal{
+		date:   date,
+		input:  input,
+		store:  store,
+		styles: styles,
+	}
+}
+
+func (m *SearchModal) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *SearchModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case searchResultsMsg:
+		if msg.seq == m.seq {
+			m.results = msg.results
+			m.searching = false
+			m.searched = true
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+
+		case "enter":
+			// Search, or jump to the selected result once the query is unchanged
+			if m.searched && len(m.results) > 0 {
+				date := m.results[m.selected].Date
+				return m, tea.Sequence(
+					func() tea.Msg { return ModalCloseMsg(true) },
+					func() tea.Msg { return JumpToDateMsg(date) },
+				)
+			}
+			return m, m.search()
+
+		case "down", "ctrl+n":
+			if m.selected < len(m.results)-1 {
+				m.selected++
+			}
+			return m, nil
+
+		case "up", "ctrl+p":
+			if m.selected > 0 {
+				m.selected--
+			}
+			return m, nil
+		}
+
+		var cmd tea.Cmd
+		old := m.input.Value()
+		m.input, cmd = m.input.Update(msg)
+		if m.input.Value() != old {
+			// Results of the old query are no longer wanted
+			m.searched = false
+			m.searching = false
+			m.seq++
+		}
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// search starts looking for the query in the background
+func (m *SearchModal) search() tea.Cmd {
+	m.results = nil
+	m.selected = 0
+	m.seq++
+	query := strings.TrimSpace(m.input.Value())
+	if query == "" {
+		m.searched = true
+		return nil
+	}
+	m.searching = true
+	return searchCmd(m.seq, m.store, m.date, query)
+}
+
+// searchCmd loads the events around date and keeps the matches
+func searchCmd(seq int, store storage.Store, date time.Time, query string) tea.Cmd {
+	return func() tea.Msg {
+		from := date.AddDate(0, 0, -searchDays)
+		to := date.AddDate(0, 0, searchDays)
+		days, err := store.LoadRange(from, to)
+		if err != nil {
+			return searchResultsMsg{seq: seq}
+		}
+
+		// Multi-day events appear on every day they cover; list them once
+		var results []EventWithDate
+		seen := make(map[string]bool)
+		var keys []string
+		for key := range days {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			day, _ := time.ParseInLocation(model.DateFormat, key, date.Location())
+			for _, evt := range days[key] {
+				id := spanKey(evt) + "@" + evt.RecurrenceID
+				if seen[id] || !evt.Matches(query) {
+					continue
+				}
+				seen[id] = true
+				results = append(results, EventWithDate{Date: day, Event: evt})
+				if len(results) == maxSearchResults {
+					return searchResultsMsg{seq: seq, results: results}
+				}
+			}
+		}
+		return searchResultsMsg{seq: seq, results: results}
+	}
+}
+
+func (m *SearchModal) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33")).Render("🔍 Search Events")
+	content := []string{title, "", m.input.View(), ""}
+
+	gray := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	switch {
+	case m.searching:
+		content = append(content, gray.Render("Searching…"))
+	case !m.searched:
+		content = append(content, gray.Render("Matches titles, descriptions, locations, links, attendees and categories"))
+	case len(m.results) == 0:
+		content = append(content, gray.Render("No events found"))
+	default:
+		// Keep the selected result in view
+		rows := m.height - 14
+		if rows < 5 {
+			rows = 5
+		}
+		first := 0
+		if m.selected >= rows {
+			first = m.selected - rows + 1
+		}
+		for i, r := range m.results {
+			if i < first || i >= first+rows {
+				continue
+			}
+			line := fmt.Sprintf("%s  %s  %s",
+				r.Date.Format("Mon Jan 2 2006"),
+				eventTimeLabel(r.Event, r.Date),
+				eventTitle(r.Event))
+			if r.Event.Location != "" {
+				line += gray.Render("  📍 " + r.Event.Location)
+			}
+			if i == m.selected {
+				line = lipgloss.NewStyle().
+					Background(lipgloss.Color("238")).
+					Foreground(lipgloss.Color("15")).
+					Bold(true).
+					Render("▶ " + line)
+			} else {
+				line = "  " + line
+			}
+			content = append(content, line)
+		}
+		if len(m.results) == maxSearchResults {
+			content = append(content, gray.Render(fmt.Sprintf("Showing the first %d matches", maxSearchResults)))
+		}
+	}
+
+	content = append(content, "", gray.Render("Enter Search / go to event · ↑↓ Select · Esc Close"))
+
+	modal := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2).
+		Width(80).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
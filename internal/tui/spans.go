@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"bubblecal/internal/model"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// spanLanes assigns each multi-day event in a row of dates (a week in the
+// month or week view) to a lane, so a bar stays on the same line in every
+// cell it crosses.
+type spanLanes struct {
+	lanes map[string]int // spanKey -> lane
+	first map[string]int // spanKey -> first column it appears in
+	count int
+}
+
+// assignSpanLanes computes lanes for the multi-day events accepted by
+// include across the given dates. eventsByDay holds the events of each date.
+func assignSpanLanes(dates []time.Time, eventsByDay [][]*model.Event, include func(*model.Event, time.Time) bool) spanLanes {
+	type span struct {
+		event      *model.Event
+		first, last int // columns covered in this row
+	}
+
+	var spans []*span
+	byKey := make(map[string]*span)
+	for col, events := range eventsByDay {
+		for _, evt := range events {
+			if !evt.IsMultiDay() || !include(evt, dates[col]) {
+				continue
+			}
+			if sp, ok := byKey[spanKey(evt)]; ok {
+				sp.last = col
+				continue
+			}
+			sp := &span{event: evt, first: col, last: col}
+			byKey[spanKey(evt)] = sp
+			spans = append(spans, sp)
+		}
+	}
+
+	// Earlier and longer spans get the top lanes
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].first != spans[j].first {
+			return spans[i].first < spans[j].first
+		}
+		if spans[i].event.StartDate != spans[j].event.StartDate {
+			return spans[i].event.StartDate < spans[j].event.StartDate
+		}
+		return spans[i].event.Title < spans[j].event.Title
+	})
+
+	result := spanLanes{lanes: make(map[string]int), first: make(map[string]int)}
+	var laneEnds []int // last column used by each lane
+	for _, sp := range spans {
+		lane := -1
+		for i, end := range laneEnds {
+			if end < sp.first {
+				lane = i
+				break
+			}
+		}
+		if lane == -1 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, 0)
+		}
+		laneEnds[lane] = sp.last
+		result.lanes[spanKey(sp.event)] = lane
+		result.first[spanKey(sp.event)] = sp.first
+	}
+	result.count = len(laneEnds)
+	return result
+}
+
+// spanKey identifies one multi-day bar. Occurrences of a recurring series
+// share the series ID, so the start date is part of the key.
+func spanKey(evt *model.Event) string {
+	return evt.ID + "@" + evt.StartDate
+}
+
+// renderSpanBar draws the piece of a multi-day event's bar that falls in
+// one cell of exactly width columns. showTitle is set for the first cell
+// of the bar in each row.
+func renderSpanBar(evt *model.Event, date time.Time, showTitle bool, width int, color lipgloss.Color) string {
+	if width <= 0 {
+		return ""
+	}
+	key := date.Format(model.DateFormat)
+	isStart := key == evt.StartDate
+	isEnd := key == evt.EndDate
+
+	var b strings.Builder
+	inner := width
+	if isStart {
+		b.WriteString(" ")
+		inner--
+	}
+	if isEnd {
+		inner--
+	}
+
+	if showTitle {
+		label := eventTitle(evt)
+		if seg := evt.SegmentOn(date); isStart && !seg.AllDay {
+			label = seg.Start + " " + label
+		}
+		label = truncateText(label, inner)
+		b.WriteString(label)
+		inner -= lipgloss.Width(label)
+	}
+	if inner > 0 {
+		b.WriteString(strings.Repeat("━", inner))
+	}
+	if isEnd {
+		b.WriteString(" ")
+	}
+
+	return withStatus(lipgloss.NewStyle().Foreground(color), evt).Render(b.String())
+}
+
+// truncateText shortens s to at most width cells, ending in an ellipsis
+func truncateText(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// eventTimeLabel formats an event's time on date, adding "(2/3)" for the
+// day of a multi-day event
+func eventTimeLabel(evt *model.Event, date time.Time) string {
+	seg := evt.SegmentOn(date)
+	if evt.IsOvernight() {
+		// "22:00-02:00 (+1)" on the first day, "→02:00" on the second
+		if seg.ContinuesBefore {
+			return seg.Label()
+		}
+		return fmt.Sprintf("%s-%s (+1)", evt.StartTime, evt.EndTime)
+	}
+	label := seg.Label()
+	if day := spanDayLabel(evt, date); day != "" {
+		label += " " + day
+	}
+	return label
+}
+
+// spanDayLabel returns "(2/3)" for the second day of a three-day event,
+// or "" for single-day events
+func spanDayLabel(evt *model.Event, date time.Time) string {
+	if !evt.IsMultiDay() {
+		return ""
+	}
+	start, _, err := evt.Dates()
+	if err != nil {
+		return ""
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, start.Location())
+	n := int(day.Sub(start).Hours()/24+0.5) + 1
+	return fmt.Sprintf("(%d/%d)", n, evt.SpanDays())
+}
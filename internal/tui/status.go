@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// withStatus dims tentative events and strikes through cancelled ones
+func withStatus(style lipgloss.Style, evt *model.Event) lipgloss.Style {
+	switch {
+	case evt.IsCancelled():
+		return style.Strikethrough(true).Faint(true)
+	case evt.IsTentative():
+		return style.Faint(true).Italic(true)
+	}
+	return style
+}
+
+// statusLabel describes a status for the status line
+func statusLabel(status string) string {
+	if status == "" {
+		return "no status"
+	}
+	return status
+}
+
+// cycleStatus moves evt, shown on date, on to its next status. Occurrences
+// of a recurring series are changed on their own.
+func cycleStatus(store storage.Store, date time.Time, evt *model.Event) (string, error) {
+	stored, day := evt.FromDisplay(date)
+	updated := stored.Clone()
+	updated.Status = stored.NextStatus()
+	if stored.InSeries() {
+		if err := storage.UpdateOccurrence(store, day, stored, updated, storage.ScopeThis); err != nil {
+			return "", err
+		}
+	} else if err := store.UpdateEvent(day, stored, updated); err != nil {
+		return "", err
+	}
+	return updated.Status, nil
+}
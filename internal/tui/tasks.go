@@ -0,0 +1,383 @@
+package tui
+
+import (
+	"fmt"
+	"bubblecal/internal/config"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taskLabel renders a task for the agenda and list: checkbox, due time,
+// title in its category's color and priority. Overdue tasks say when they
+// were due.
+func taskLabel(cfg *config.Config, t *model.Task, date time.Time) string {
+	gray := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	titleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
+	if cfg != nil && t.Category != "" {
+		titleStyle = titleStyle.Foreground(lipgloss.Color(cfg.GetCategoryColor(t.Category)))
+	}
+
+	box := "☐"
+	if t.Done {
+		box = "☑"
+		titleStyle = titleStyle.Strikethrough(true).Faint(true)
+	}
+	label := gray.Render(box)
+	if t.DueTime != "" {
+		label += " " + gray.Render(t.DueTime)
+	}
+	label += " " + titleStyle.Render(t.Title)
+
+	switch t.Priority {
+	case model.PriorityHigh:
+		label += lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(" !!!")
+	case model.PriorityMedium:
+		label += lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render(" !!")
+	case model.PriorityLow:
+		label += gray.Render(" !")
+	}
+	if t.Due != date.Format(model.DateFormat) {
+		if due, err := time.Parse(model.DateFormat, t.Due); err == nil {
+			label += lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(" (due " + due.Format("Jan 2") + ")")
+		}
+	}
+	return label
+}
+
+// toggleTask marks a task done, or not done again
+func toggleTask(store storage.Store, t *model.Task) error {
+	ts, ok := store.(storage.TaskStore)
+	if !ok {
+		return fmt.Errorf("this calendar doesn't keep tasks")
+	}
+	updated := t.Clone()
+	updated.Done = !t.Done
+	return ts.SaveTask(updated)
+}
+
+// TaskModal adds or edits a task
+type TaskModal struct {
+	date        time.Time
+	editingTask *model.Task
+	inputs      []textinput.Model // title, due date, due time
+	priority    int               // index into model.Priorities
+	category    int               // index into categories, len(categories) for none
+	categories  []config.Category
+	focused     int // 0-2 the inputs, 3 priority, 4 category
+	errorMsg    string
+	store       storage.Store
+	styles      *Styles
+	width       int
+	height      int
+}
+
+const (
+	taskInputTitle = iota
+	taskInputDue
+	taskInputTime
+	taskFieldPriority
+	taskFieldCategory
+	taskFieldCount
+)
+
+// NewTaskModal opens a modal for a new task due on date, or for editing
+// task if it isn't nil
+func NewTaskModal(date time.Time, task *model.Task, styles *Styles, categories []config.Category, store storage.Store) *TaskModal {
+	m := &TaskModal{
+		date:        date,
+		editingTask: task,
+		categories:  categories,
+		category:    len(categories),
+		priority:    len(model.Priorities) - 1,
+		store:       store,
+		styles:      styles,
+	}
+
+	placeholders := []string{"What needs doing?", "YYYY-MM-DD", "HH:MM (optional)"}
+	for _, p := range placeholders {
+		input := textinput.New()
+		input.Placeholder = p
+		input.CharLimit = 100
+		m.inputs = append(m.inputs, input)
+	}
+	m.inputs[taskInputDue].SetValue(date.Format(model.DateFormat))
+
+	if task != nil {
+		m.inputs[taskInputTitle].SetValue(task.Title)
+		m.inputs[taskInputDue].SetValue(task.Due)
+		m.inputs[taskInputTime].SetValue(task.DueTime)
+		for i, p := range model.Priorities {
+			if p == task.Priority {
+				m.priority = i
+			}
+		}
+		for i, cat := range categories {
+			if strings.EqualFold(cat.Name, task.Category) {
+				m.category = i
+			}
+		}
+	}
+	m.inputs[taskInputTitle].Focus()
+	return m
+}
+
+func (m *TaskModal) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m *TaskModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+
+		case "enter", "ctrl+s":
+			if err := m.save(); err != nil {
+				m.errorMsg = err.Error()
+				return m, nil
+			}
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+
+		case "tab", "down":
+			m.setFocus((m.focused + 1) % taskFieldCount)
+			return m, nil
+
+		case "shift+tab", "up":
+			m.setFocus((m.focused + taskFieldCount - 1) % taskFieldCount)
+			return m, nil
+
+		case "left", "right":
+			step := 1
+			if msg.String() == "left" {
+				step = -1
+			}
+			switch m.focused {
+			case taskFieldPriority:
+				n := len(model.Priorities)
+				m.priority = (m.priority + step + n) % n
+				return m, nil
+			case taskFieldCategory:
+				n := len(m.categories) + 1
+				m.category = (m.category + step + n) % n
+				return m, nil
+			}
+		}
+
+		if m.focused < len(m.inputs) {
+			var cmd tea.Cmd
+			m.inputs[m.focused], cmd = m.inputs[m.focused].Update(msg)
+			return m, cmd
+		}
+	}
+	return m, nil
+}
+
+func (m *TaskModal) setFocus(field int) {
+	m.focused = field
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+	if field < len(m.inputs) {
+		m.inputs[field].Focus()
+	}
+}
+
+// buildTask validates the form and returns the task it describes
+func (m *TaskModal) buildTask() (*model.Task, error) {
+	task := &model.Task{}
+	if m.editingTask != nil {
+		task = m.editingTask.Clone()
+	}
+	task.Title = strings.TrimSpace(m.inputs[taskInputTitle].Value())
+	if task.Title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+	task.Due = strings.TrimSpace(m.inputs[taskInputDue].Value())
+	if _, err := time.Parse(model.DateFormat, task.Due); err != nil {
+		return nil, fmt.Errorf("due date must be YYYY-MM-DD")
+	}
+	task.DueTime = strings.TrimSpace(m.inputs[taskInputTime].Value())
+	if task.DueTime != "" && !isValidTime(task.DueTime) {
+		return nil, fmt.Errorf("due time must be HH:MM")
+	}
+	task.Priority = model.Priorities[m.priority]
+	task.Category = ""
+	if m.category < len(m.categories) {
+		task.Category = m.categories[m.category].Name
+	}
+	return task, nil
+}
+
+func (m *TaskModal) save() error {
+	task, err := m.buildTask()
+	if err != nil {
+		return err
+	}
+	ts, ok := m.store.(storage.TaskStore)
+	if !ok {
+		return fmt.Errorf("this calendar doesn't keep tasks")
+	}
+	return ts.SaveTask(task)
+}
+
+func (m *TaskModal) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	title := "✅ New Task"
+	if m.editingTask != nil {
+		title = "✏️ Edit Task"
+	}
+	gray := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	content := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33")).Render(title),
+		"",
+	}
+
+	priority := model.Priorities[m.priority]
+	if priority == "" {
+		priority = "none"
+	}
+	category := "none"
+	if m.category < len(m.categories) {
+		category = m.categories[m.category].Name
+	}
+	fields := []struct{ label, value string }{
+		{"📝 Title", m.inputs[taskInputTitle].View()},
+		{"📅 Due Date", m.inputs[taskInputDue].View()},
+		{"🕒 Due Time", m.inputs[taskInputTime].View()},
+		{"❗ Priority", "◀ " + priority + " ▶"},
+		{"🏷️ Category", "◀ " + category + " ▶"},
+	}
+	for i, f := range fields {
+		labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("247"))
+		fieldStyle := lipgloss.NewStyle().Margin(0, 0, 1, 0)
+		if i == m.focused {
+			labelStyle = labelStyle.Foreground(lipgloss.Color("39")).Bold(true)
+			fieldStyle = fieldStyle.BorderLeft(true).BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("39"))
+		}
+		content = append(content, fieldStyle.Render(lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render(f.label), f.value)))
+	}
+
+	if m.errorMsg != "" {
+		content = append(content, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true).
+			Render("❌ "+m.errorMsg), "")
+	}
+	content = append(content, gray.Render("Tab Next field · ←→ Change · Enter Save · Esc Cancel"))
+
+	modal := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2).
+		Width(60).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+// DeleteTaskModal asks before deleting a task
+type DeleteTaskModal struct {
+	task     *model.Task
+	store    storage.TaskStore
+	errorMsg string // why the last delete failed
+	styles   *Styles
+	width    int
+	height   int
+}
+
+func NewDeleteTaskModal(task *model.Task, styles *Styles, store storage.TaskStore) *DeleteTaskModal {
+	return &DeleteTaskModal{
+		task:   task,
+		store:  store,
+		styles: styles,
+	}
+}
+
+func (m *DeleteTaskModal) Init() tea.Cmd {
+	return nil
+}
+
+func (m *DeleteTaskModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc", "n", "N":
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+
+		case "y", "Y", "enter":
+			// Keep the modal open with the error if the delete fails
+			if err := m.store.DeleteTask(m.task); err != nil {
+				m.errorMsg = err.Error()
+				return m, nil
+			}
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+		}
+	}
+	return m, nil
+}
+
+func (m *DeleteTaskModal) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	taskTitle := m.task.Title
+	if due, err := time.Parse(model.DateFormat, m.task.Due); err == nil {
+		taskTitle = fmt.Sprintf("%s\n   Due %s %s", taskTitle, due.Format("Monday, January 2, 2006"), m.task.DueTime)
+	}
+	if m.task.Category != "" {
+		taskTitle = fmt.Sprintf("%s\n   Category: %s", taskTitle, m.task.Category)
+	}
+
+	gray := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196")).Render("⚠️  Confirm Delete"),
+		lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1).
+			Margin(1, 0).
+			Render("☐ " + strings.TrimSpace(taskTitle)),
+		lipgloss.NewStyle().Bold(true).Render("Delete this task?"),
+		"",
+		gray.Render("[Y]es / [N]o"),
+	}
+	if m.errorMsg != "" {
+		lines = append(lines, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Background(lipgloss.Color("52")).
+			Padding(0, 1).
+			Margin(1, 0).
+			Bold(true).
+			Width(52).
+			Render("❌ "+m.errorMsg))
+	}
+
+	modal := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("196")).
+		Padding(1, 3).
+		Width(60).
+		Background(lipgloss.Color("0")).
+		Render(lipgloss.JoinVertical(lipgloss.Center, lines...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"fmt"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// purgeTrashCmd empties the trash of what was deleted longer than
+// retention ago; a retention of 0 keeps everything
+func purgeTrashCmd(trash storage.TrashStore, retention time.Duration) tea.Cmd {
+	if trash == nil || retention <= 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		trash.PurgeTrashBefore(time.Now().Add(-retention))
+		return nil
+	}
+}
+
+// trashLoadedMsg carries the contents of the trash
+type trashLoadedMsg struct {
+	items []*storage.TrashedEvent
+	err   error
+}
+
+func loadTrashCmd(trash storage.TrashStore) tea.Cmd {
+	return func() tea.Msg {
+		items, err := trash.LoadTrash()
+		return trashLoadedMsg{items: items, err: err}
+	}
+}
+
+// TrashModal lists recently deleted events and tasks to restore or purge
+type TrashModal struct {
+	trash    storage.TrashStore
+	store    storage.Store // restored events are saved through it
+	items    []*storage.TrashedEvent
+	loaded   bool
+	selected int
+	purging  bool // x was pressed once; press again to purge
+	message  string
+	errorMsg string
+	styles   *Styles
+	width    int
+	height   int
+}
+
+func NewTrashModal(trash storage.TrashStore, store storage.Store, styles *Styles) *TrashModal {
+	return &TrashModal{
+		trash:  trash,
+		store:  store,
+		styles: styles,
+	}
+}
+
+func (m *TrashModal) Init() tea.Cmd {
+	return loadTrashCmd(m.trash)
+}
+
+func (m *TrashModal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case trashLoadedMsg:
+		m.items = msg.items
+		m.loaded = true
+		if msg.err != nil {
+			m.errorMsg = msg.err.Error()
+		}
+		if m.selected >= len(m.items) {
+			m.selected = len(m.items) - 1
+		}
+		if m.selected < 0 {
+			m.selected = 0
+		}
+
+	case tea.KeyMsg:
+		key := msg.String()
+		if key != "x" {
+			m.purging = false
+		}
+		switch key {
+		case "ctrl+c", "esc", "q":
+			return m, func() tea.Msg { return ModalCloseMsg(true) }
+
+		case "down", "j":
+			if m.selected < len(m.items)-1 {
+				m.selected++
+			}
+
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+
+		case "enter", "r":
+			// Restore by saving the event again, so it can be undone
+			if item := m.selectedItem(); item != nil {
+				m.errorMsg = ""
+				if err := m.restore(item); err != nil {
+					m.errorMsg = err.Error()
+					return m, nil
+				}
+				m.trash.PurgeTrashed(item)
+				m.remove(item)
+				if item.Task != nil {
+					m.message = "Restored task " + item.Task.Title
+				} else {
+					m.message = "Restored " + item.Event.Title + " to " + item.Date.Format("Mon Jan 2")
+				}
+				return m, loadTrashCmd(m.trash)
+			}
+
+		case "x":
+			if item := m.selectedItem(); item != nil {
+				if !m.purging {
+					m.purging = true
+					return m, nil
+				}
+				m.purging = false
+				m.errorMsg = ""
+				if err := m.trash.PurgeTrashed(item); err != nil {
+					m.errorMsg = err.Error()
+					return m, nil
+				}
+				m.remove(item)
+				m.message = "Deleted " + item.Title() + " for good"
+				return m, loadTrashCmd(m.trash)
+			}
+		}
+	}
+	return m, nil
+}
+
+// restore saves a deleted event or task again
+func (m *TrashModal) restore(item *storage.TrashedEvent) error {
+	if item.Task == nil {
+		return m.store.SaveEvent(item.Date, item.Event.Clone())
+	}
+	tasks, ok := m.store.(storage.TaskStore)
+	if !ok {
+		return fmt.Errorf("this calendar doesn't keep tasks")
+	}
+	return tasks.SaveTask(item.Task.Clone())
+}
+
+func (m *TrashModal) selectedItem() *storage.TrashedEvent {
+	if m.selected >= 0 && m.selected < len(m.items) {
+		return m.items[m.selected]
+	}
+	return nil
+}
+
+// remove drops item from the list until the trash is read again
+func (m *TrashModal) remove(item *storage.TrashedEvent) {
+	for i, it := range m.items {
+		if it == item {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			break
+		}
+	}
+	if m.selected >= len(m.items) && m.selected > 0 {
+		m.selected--
+	}
+}
+
+func (m *TrashModal) View() string {
+	if m.width == 0 || m.height == 0 {
+		return "Loading..."
+	}
+
+	gray := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	content := []string{
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("33")).Render("🗑  Recently Deleted"),
+		"",
+	}
+
+	switch {
+	case !m.loaded:
+		content = append(content, gray.Render("Loading…"))
+	case len(m.items) == 0:
+		content = append(content, gray.Render("The trash is empty"))
+	default:
+		// Keep the selected entry in view
+		rows := m.height - 14
+		if rows < 5 {
+			rows = 5
+		}
+		first := 0
+		if m.selected >= rows {
+			first = m.selected - rows + 1
+		}
+		for i, item := range m.items {
+			if i < first || i >= first+rows {
+				continue
+			}
+			var line string
+			if item.Task != nil {
+				line = "Task  " + item.Task.Title
+				if due, err := time.Parse(model.DateFormat, item.Task.Due); err == nil {
+					line += gray.Render("  due " + due.Format("Mon Jan 2 2006"))
+				}
+			} else {
+				line = fmt.Sprintf("%s  %s  %s",
+					item.Date.Format("Mon Jan 2 2006"),
+					eventTimeLabel(item.Event, item.Date),
+					eventTitle(item.Event))
+			}
+			line += gray.Render("  deleted " + deletedAgo(item.Deleted))
+			if i == m.selected {
+				line = lipgloss.NewStyle().
+					Background(lipgloss.Color("238")).
+					Foreground(lipgloss.Color("15")).
+					Bold(true).
+					Render("▶ " + line)
+			} else {
+				line = "  " + line
+			}
+			content = append(content, line)
+		}
+	}
+
+	content = append(content, "")
+	if m.message != "" {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✓ "+m.message), "")
+	}
+	if m.errorMsg != "" {
+		content = append(content, lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Bold(true).
+			Render("❌ "+m.errorMsg), "")
+	}
+	if m.purging {
+		content = append(content, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Render("Press x again to delete it for good"), "")
+	}
+	content = append(content, gray.Render("Enter Restore · x Delete for good · ↑↓ Select · Esc Close"))
+
+	modal := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("39")).
+		Padding(1, 2).
+		Width(80).
+		Render(lipgloss.JoinVertical(lipgloss.Left, content...))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, modal)
+}
+
+// deletedAgo says how long ago t was, roughly
+func deletedAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"fmt"
+	"bubblecal/internal/config"
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchInterval is how often the calendar and config files are checked
+// for changes made by other programs
+const watchInterval = time.Second
+
+// watcher polls the store's files and config.json. Only the running
+// watchCmd touches it, one check at a time.
+type watcher struct {
+	store    storage.Scanner // nil if the store has no files to watch
+	stamps   map[string]string
+	from, to string // days of the last scan, as "2006-01-02"
+	config   string // stamp of config.json
+	primed   bool   // the first check has taken stamps
+}
+
+// FilesChangedMsg reports what a watchCmd found. One is sent after every
+// check, changes or not, so the model can schedule the next check.
+type FilesChangedMsg struct {
+	changes storage.Changes
+	config  bool // config.json changed
+}
+
+func newWatcher(store storage.Store) *watcher {
+	w := &watcher{}
+	w.store, _ = store.(storage.Scanner)
+	return w
+}
+
+// watchCmd checks for changes to the days from from to to, the days
+// loaded, once watchInterval has passed
+func watchCmd(w *watcher, from, to time.Time) tea.Cmd {
+	// An event in another time zone can be stored on the day before or
+	// after
+	if !from.IsZero() && !to.IsZero() {
+		from, to = from.AddDate(0, 0, -1), to.AddDate(0, 0, 1)
+	}
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return w.check(from, to)
+	})
+}
+
+// check compares the files with the previous check. The first check
+// only takes stamps. Days that weren't watched before count as changed,
+// since nothing noticed what happened to them in the meantime.
+func (w *watcher) check(from, to time.Time) FilesChangedMsg {
+	var msg FilesChangedMsg
+	if w.store != nil {
+		if stamps, err := w.store.Scan(from, to); err == nil {
+			first, last := from.Format(model.DateFormat), to.Format(model.DateFormat)
+			if w.primed {
+				// Days that are no longer watched haven't changed
+				before := make(map[string]string, len(w.stamps))
+				for key, stamp := range w.stamps {
+					if day, ok := strings.CutPrefix(key, "days/"); !ok || day >= first && day <= last {
+						before[key] = stamp
+					}
+				}
+				// Days that weren't watched have, whatever the scan says
+				for date := from; !from.IsZero() && !date.After(to); date = date.AddDate(0, 0, 1) {
+					if day := date.Format(model.DateFormat); day < w.from || day > w.to {
+						before["days/"+day] = "unwatched"
+					}
+				}
+				msg.changes = storage.Compare(before, stamps)
+			}
+			w.stamps = stamps
+			w.from, w.to = first, last
+		}
+	}
+
+	stamp := ""
+	if path, err := config.Path(); err == nil {
+		if info, err := os.Stat(path); err == nil {
+			stamp = fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	msg.config = w.primed && stamp != w.config
+	w.config = stamp
+	w.primed = true
+	return msg
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"bubblecal/internal/model"
+	"bubblecal/internal/storage"
+	"testing"
+	"time"
+)
+
+func TestWatcherLooksAtLoadedDays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store := storage.NewFileStore(t.TempDir())
+	w := newWatcher(store)
+	day := func(s string) time.Time {
+		d, _ := time.ParseInLocation(model.DateFormat, s, time.Local)
+		return d
+	}
+	save := func(date string) {
+		if err := store.SaveEvent(day(date), &model.Event{StartTime: "09:00", Title: "Standup"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	from, to := day("2025-08-01"), day("2025-08-31")
+
+	w.check(from, to)
+	save("2025-08-13")
+	save("2025-10-01") // not loaded, so not looked at
+	if msg := w.check(from, to); len(msg.changes.Days) != 1 || msg.changes.Days[0] != "2025-08-13" || msg.changes.Shared {
+		t.Errorf("got %+v, want 2025-08-13 changed", msg.changes)
+	}
+	if _, ok := w.stamps["days/2025-10-01"]; ok {
+		t.Error("scanned a day that isn't loaded")
+	}
+
+	// Moving on to October reports its days, which nothing watched
+	msg := w.check(day("2025-09-01"), day("2025-10-31"))
+	if len(msg.changes.Days) != 61 || msg.changes.Shared {
+		t.Errorf("got %d days changed, want the 61 days now watched", len(msg.changes.Days))
+	}
+	if msg := w.check(day("2025-09-01"), day("2025-10-31")); !msg.changes.Empty() {
+		t.Errorf("got %+v with nothing changed", msg.changes)
+	}
+}
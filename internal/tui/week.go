@@ -3,7 +3,7 @@ package tui
 import (
 	"fmt"
 	"bubblecal/internal/config"
-	"bubblecal/internal/storage"
+	"bubblecal/internal/model"
 	"strings"
 	"time"
 
@@ -19,16 +19,18 @@ type WeekViewModel struct {
 	height       int
 	showMiniMonth bool
 	config       *config.Config
+	data         *calendarData
 }
 
 // NewWeekViewModel creates a new week view model
-func NewWeekViewModel(selectedDate *time.Time, selectedHour *int, styles *Styles, config *config.Config) *WeekViewModel {
+func NewWeekViewModel(selectedDate *time.Time, selectedHour *int, styles *Styles, config *config.Config, data *calendarData) *WeekViewModel {
 	return &WeekViewModel{
 		selectedDate: selectedDate,
 		selectedHour: selectedHour,
 		styles:       styles,
 		showMiniMonth: true, // Show by default
 		config:       config,
+		data:         data,
 	}
 }
 
@@ -48,6 +50,9 @@ func (w *WeekViewModel) View() string {
 	
 	sel := *w.selectedDate
 	weekStart := sel.AddDate(0, 0, -int(sel.Weekday()))
+	if !w.data.covers(weekStart, weekStart.AddDate(0, 0, 6)) {
+		return loadingView(w.width, w.height)
+	}
 	
 	var lines []string
 	
@@ -64,13 +69,16 @@ func (w *WeekViewModel) View() string {
 	for d := 0; d < 7; d++ {
 		date := weekStart.AddDate(0, 0, d)
 		label := fmt.Sprintf("%s %d", date.Weekday().String()[:3], date.Day())
+		if w.data.noteOn(date) != "" {
+			label += " " + noteMarker
+		}
 		
 		style := lipgloss.NewStyle().
 			Width(colWidth).
 			Align(lipgloss.Center).
 			Bold(true)
 		
-		if sameDay(date, time.Now()) {
+		if sameDay(date, currentTime(w.config)) {
 			style = style.
 				Background(w.styles.TodayDate.GetBackground()).
 				Foreground(w.styles.TodayDate.GetForeground())
@@ -81,6 +89,19 @@ func (w *WeekViewModel) View() string {
 	lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, headerCells...))
 	lines = append(lines, strings.Repeat("─", w.width-4))
 	
+	// Collect the week's events
+	dates := make([]time.Time, 7)
+	eventsByDay := make([][]*model.Event, 7)
+	for d := 0; d < 7; d++ {
+		dates[d] = weekStart.AddDate(0, 0, d)
+		eventsByDay[d] = w.data.eventsOn(dates[d])
+	}
+	
+	// Multi-day events covering whole days become bars in the all-day row
+	lanes := assignSpanLanes(dates, eventsByDay, func(evt *model.Event, date time.Time) bool {
+		return evt.SegmentOn(date).AllDay
+	})
+	
 	// All-day events row
 	var allDayRow []string
 	
@@ -88,8 +109,7 @@ func (w *WeekViewModel) View() string {
 	maxAllDayHeight := 1
 	var allDayContents []string
 	for d := 0; d < 7; d++ {
-		date := weekStart.AddDate(0, 0, d)
-		content := w.getAllDayEvents(date)
+		content := w.getAllDayEvents(dates[d], d, eventsByDay[d], lanes, colWidth)
 		allDayContents = append(allDayContents, content)
 		if content != "" {
 			height := strings.Count(content, "\n") + 1
@@ -111,13 +131,13 @@ func (w *WeekViewModel) View() string {
 		date := weekStart.AddDate(0, 0, d)
 		allDayEvents := allDayContents[d]
 		
+		// No padding here: each line pads itself so bars can span columns
 		cellStyle := lipgloss.NewStyle().
 			Width(colWidth).
-			Height(maxAllDayHeight).
-			Padding(0, 1)
+			Height(maxAllDayHeight)
 		
 		// Subtle background for today
-		if sameDay(date, time.Now()) {
+		if sameDay(date, currentTime(w.config)) {
 			cellStyle = cellStyle.Background(lipgloss.Color("234"))
 		}
 		
@@ -138,19 +158,17 @@ func (w *WeekViewModel) View() string {
 	
 	// Scan all days in the week for events outside default range
 	for d := 0; d < 7; d++ {
-		date := weekStart.AddDate(0, 0, d)
-		events, _ := storage.LoadDayEvents(date)
-		
-		for _, evt := range events {
-			if !evt.IsAllDay() && evt.StartTime != "" {
-				var hour int
-				if _, err := fmt.Sscanf(evt.StartTime, "%d:", &hour); err == nil {
-					if hour < startHour {
-						startHour = hour
-					}
-					if hour > endHour {
-						endHour = hour
-					}
+		for _, evt := range eventsByDay[d] {
+			seg := evt.SegmentOn(dates[d])
+			if first, last, ok := seg.HourRange(); ok {
+				if first < startHour {
+					startHour = first
+				}
+				if !evt.IsMultiDay() {
+					last = first // single-day events only need their start hour
+				}
+				if last > endHour {
+					endHour = last
 				}
 			}
 		}
@@ -173,7 +191,7 @@ func (w *WeekViewModel) View() string {
 		var cellContents []string
 		for d := 0; d < 7; d++ {
 			date := weekStart.AddDate(0, 0, d)
-			content := w.getHourEvents(date, h)
+			content := w.getHourEvents(date, h, eventsByDay[d])
 			cellContents = append(cellContents, content)
 			if content != "" {
 				height := strings.Count(content, "\n") + 1
@@ -203,7 +221,7 @@ func (w *WeekViewModel) View() string {
 				Padding(0, 1)
 			
 			// Subtle background for today's column
-			if sameDay(date, time.Now()) {
+			if sameDay(date, currentTime(w.config)) {
 				cellStyle = cellStyle.Background(lipgloss.Color("234"))
 			}
 			
@@ -238,73 +256,78 @@ func (w *WeekViewModel) GetSelectedHour() string {
 	return ""  
 }
 
-func (w *WeekViewModel) getAllDayEvents(date time.Time) string {
-	events, _ := storage.LoadDayEvents(date)
+func (w *WeekViewModel) getAllDayEvents(date time.Time, col int, events []*model.Event, lanes spanLanes, colWidth int) string {
+	// One line per span lane, blank where no bar crosses this day
+	lines := make([]string, lanes.count)
 	
-	var allDayTitles []string
 	for _, evt := range events {
-		if evt.IsAllDay() {
-			title := evt.Title
+		// Get category color
+		categoryColor := lipgloss.Color("15") // Default white
+		if w.config != nil && evt.PrimaryCategory() != "" {
+			categoryColor = lipgloss.Color(w.config.GetCategoryColor(evt.PrimaryCategory()))
+		}
+		
+		if lane, ok := lanes.lanes[spanKey(evt)]; ok && evt.SegmentOn(date).AllDay {
+			showTitle := lanes.first[spanKey(evt)] == col
+			lines[lane] = renderSpanBar(evt, date, showTitle, colWidth, categoryColor)
+			continue
+		}
+		
+		if evt.IsAllDay() && !evt.IsMultiDay() {
 			// Calculate max length based on column width
-			colWidth := (w.width - 10) / 7
-			maxLen := colWidth - 4 // Account for padding
-			if len(title) > maxLen && maxLen > 3 {
-				title = title[:maxLen-1] + "…"
-			}
-			
-			// Get category color
-			categoryColor := lipgloss.Color("15") // Default white
-			if w.config != nil && evt.Category != "" {
-				categoryColor = lipgloss.Color(w.config.GetCategoryColor(evt.Category))
-			}
+			title := truncateText(eventTitle(evt), colWidth-4) // Account for padding
 			
 			// Apply color to the title
-			coloredTitle := lipgloss.NewStyle().
-				Foreground(categoryColor).
+			coloredTitle := withStatus(lipgloss.NewStyle().Foreground(categoryColor), evt).
 				Render(title)
 			
-			allDayTitles = append(allDayTitles, coloredTitle)
+			lines = append(lines, " "+coloredTitle)
 		}
 	}
 	
-	if len(allDayTitles) > 0 {
+	if len(lines) > 0 {
 		// Return each all-day event on its own line
-		return strings.Join(allDayTitles, "\n")
+		return strings.Join(lines, "\n")
 	}
 	return ""
 }
 
-func (w *WeekViewModel) getHourEvents(date time.Time, hour int) string {
-	events, _ := storage.LoadDayEvents(date)
-	
+func (w *WeekViewModel) getHourEvents(date time.Time, hour int, events []*model.Event) string {
 	var hourEvents []string
 	for _, evt := range events {
-		if evt.IsAllDay() {
+		seg := evt.SegmentOn(date)
+		first, last, ok := seg.HourRange()
+		if !ok {
 			continue
 		}
 		
+		// Get category color
+		categoryColor := lipgloss.Color("15") // Default white
+		if w.config != nil && evt.PrimaryCategory() != "" {
+			categoryColor = lipgloss.Color(w.config.GetCategoryColor(evt.PrimaryCategory()))
+		}
+		
 		// Check if event starts at this hour
-		if strings.HasPrefix(evt.StartTime, fmt.Sprintf("%02d:", hour)) {
-			title := evt.Title
-			// Calculate max length based on column width
-			colWidth := (w.width - 10) / 7
-			maxLen := colWidth - 2 // Account for padding
-			if len(title) > maxLen && maxLen > 3 {
-				title = title[:maxLen-1] + "…"
+		if first == hour {
+			title := eventTitle(evt)
+			if seg.ContinuesBefore {
+				title = "→" + title
 			}
-			
-			// Get category color
-			categoryColor := lipgloss.Color("15") // Default white
-			if w.config != nil && evt.Category != "" {
-				categoryColor = lipgloss.Color(w.config.GetCategoryColor(evt.Category))
+			if seg.ContinuesAfter {
+				title += "→"
 			}
+			// Calculate max length based on column width
+			colWidth := (w.width - 10) / 7
+			title = truncateText(title, colWidth-2) // Account for padding
 			
 			// Apply color to the title
-			coloredTitle := lipgloss.NewStyle().
-				Foreground(categoryColor).
+			coloredTitle := withStatus(lipgloss.NewStyle().Foreground(categoryColor), evt).
 				Render(title)
 			
 			hourEvents = append(hourEvents, coloredTitle)
+		} else if evt.IsMultiDay() && hour > first && hour <= last {
+			// Keep drawing multi-day events as a block down the column
+			hourEvents = append(hourEvents, lipgloss.NewStyle().Foreground(categoryColor).Render("┃"))
 		}
 	}
 	
@@ -367,7 +390,7 @@ func (w *WeekViewModel) renderMiniMonth(weekStart time.Time) string {
 		inCurrentWeek := !dateOnly.Before(weekStartOnly) && !dateOnly.After(weekEndOnly)
 		
 		// Highlight today with special background (but preserve week color if in week)
-		if sameDay(date, time.Now()) {
+		if sameDay(date, currentTime(w.config)) {
 			if inCurrentWeek {
 				// Today AND in current week - use red foreground with today background
 				style = style.